@@ -0,0 +1,203 @@
+// Package api is the client of the Safe Browsing v5 REST API.
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/JILeXanDR/gsb-v5-tests/internal/logging"
+	codegen "github.com/JILeXanDR/gsb-v5-tests/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// DefaultBaseURL is the address of the public Safe Browsing API.
+	DefaultBaseURL = "https://safebrowsing.googleapis.com"
+	// DefaultTimeout limits every request, including reading the response body. Lists are downloaded in
+	// a single response, so it's generous.
+	DefaultTimeout = time.Minute
+)
+
+// API is the part of the Safe Browsing API the library uses. The methods return the decoded response and its raw body.
+type API interface {
+	V5alpha1HashLists(ctx context.Context) (*codegen.ListHashListsResponse, []byte, error)
+	V5alpha1HashListsBatchGet(ctx context.Context, names []string, versions [][]byte) (*codegen.ListHashListsResponse, []byte, error)
+	V5alpha1HashesSearch(ctx context.Context, hashPrefixes [][]byte) (*codegen.SearchHashesResponse, []byte, error)
+}
+
+// Client calls the Safe Browsing API with an API key.
+type Client struct {
+	key        string
+	baseURL    string
+	httpClient *http.Client
+	timeout    time.Duration
+	logger     logging.Logger
+}
+
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the client the requests are sent with, e.g. one with a proxy or client certificates.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL sets the address of the API, e.g. of a test server.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithTimeout limits every request. Zero disables the limit, so only the context of the call is respected.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger of the requests. The key is redacted from the messages.
+func WithLogger(logger logging.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+func NewClient(key string, options ...ClientOption) (*Client, error) {
+	if key == "" {
+		return nil, errors.New("API key is not set")
+	}
+
+	c := &Client{
+		key:     key,
+		baseURL: DefaultBaseURL,
+		// The client is shared by all requests, so connections are reused.
+		httpClient: &http.Client{},
+		timeout:    DefaultTimeout,
+		logger:     logging.Default(),
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	c.logger = logging.Redacting(c.logger, key)
+
+	return c, nil
+}
+
+// GET https://safebrowsing.googleapis.com/v5alpha1/hashLists
+func (c *Client) V5alpha1HashLists(ctx context.Context) (*codegen.ListHashListsResponse, []byte, error) {
+	var response codegen.ListHashListsResponse
+
+	body, err := c.request(ctx, "v5alpha1/hashLists", nil, &response)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &response, body, nil
+}
+
+// GET https://safebrowsing.googleapis.com/v5alpha1/hashLists:batchGet
+//
+// The versions are the versions of the lists the client already has, in the same order as names. An empty version
+// means that the list is fetched for the first time.
+func (c *Client) V5alpha1HashListsBatchGet(ctx context.Context, names []string, versions [][]byte) (*codegen.ListHashListsResponse, []byte, error) {
+	query := url.Values{}
+
+	for _, name := range names {
+		query.Add("names", name)
+	}
+
+	// Versions are left out completely when all lists are fetched for the first time.
+	if slices.ContainsFunc(versions, func(version []byte) bool { return len(version) > 0 }) {
+		for _, version := range versions {
+			query.Add("version", base64.StdEncoding.EncodeToString(version))
+		}
+	}
+
+	var response codegen.ListHashListsResponse
+
+	body, err := c.request(ctx, "v5alpha1/hashLists:batchGet", query, &response)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &response, body, nil
+}
+
+// GET https://safebrowsing.googleapis.com/v5alpha1/hashes:search
+func (c *Client) V5alpha1HashesSearch(ctx context.Context, hashPrefixes [][]byte) (*codegen.SearchHashesResponse, []byte, error) {
+	query := url.Values{}
+
+	for _, prefix := range hashPrefixes {
+		query.Add("hashPrefixes", base64.StdEncoding.EncodeToString(prefix))
+	}
+
+	var response codegen.SearchHashesResponse
+
+	body, err := c.request(ctx, "v5alpha1/hashes:search", query, &response)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &response, body, nil
+}
+
+func (c *Client) request(ctx context.Context, path string, query url.Values, result proto.Message) ([]byte, error) {
+	rawURL := fmt.Sprintf("%s/%s", c.baseURL, path)
+	if len(query) > 0 {
+		rawURL += "?" + query.Encode()
+	}
+
+	c.logger.Printf("send request: %s", rawURL)
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The key is sent in the header rather than the query, so it isn't written to the logs of proxies and servers.
+	req.Header.Set("X-Goog-Api-Key", c.key)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return nil, newError(rawURL, resp, body)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("request result length: %d", len(body))
+
+	unmarshaler := proto.UnmarshalOptions{}
+
+	if err := unmarshaler.Unmarshal(body, result); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	codegen "github.com/JILeXanDR/gsb-v5-tests/proto"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func Test_apiMethods(t *testing.T) {
+	t.Skip()
+
+	require.NoError(t, godotenv.Load("../../.env"))
+
+	apiKey := os.Getenv("GSB_API_KEY")
+	require.NotEmpty(t, apiKey, "GSB_API_KEY variable is not set")
+
+	api, err := NewClient(apiKey)
+	require.NoError(t, err)
+
+	t.Run("V5alpha1HashLists", func(t *testing.T) {
+		result, body, err := api.V5alpha1HashLists(context.TODO())
+		require.NoError(t, err)
+		require.NotEmpty(t, result)
+		require.NotEmpty(t, body)
+
+		writeFile(t, "../../testdata/hashLists.bin", body)
+	})
+
+	t.Run("V5alpha1HashListsBatchGet", func(t *testing.T) {
+		result, body, err := api.V5alpha1HashListsBatchGet(context.TODO(), []string{"gc", "se", "mw", "uws", "uwsa", "pha"}, nil)
+		require.NoError(t, err)
+		require.NotEmpty(t, result)
+		require.NotEmpty(t, body)
+
+		writeFile(t, "../../testdata/hashLists:batchGet.bin", body)
+	})
+
+	t.Run("V5alpha1HashListsBatchGet gc", func(t *testing.T) {
+		result, body, err := api.V5alpha1HashListsBatchGet(context.TODO(), []string{"gc"}, nil)
+		require.NoError(t, err)
+		require.Len(t, result.GetHashLists(), 1)
+		require.NotEmpty(t, result.GetHashLists()[0].GetSha256Checksum())
+
+		writeFile(t, "../../testdata/hashLists:batchGet-gc.bin", body)
+	})
+
+	t.Run("V5alpha1HashesSearch", func(t *testing.T) {
+		prefix := sha256.Sum256([]byte("testsafebrowsing.appspot.com/s/phishing.html"))
+
+		result, body, err := api.V5alpha1HashesSearch(context.TODO(), [][]byte{prefix[:4]})
+		require.NoError(t, err)
+		require.NotEmpty(t, result)
+		require.NotEmpty(t, body)
+
+		writeFile(t, "../../testdata/hashes:search.bin", body)
+	})
+}
+
+// newTestServer serves the response to every request and records the requests.
+func newTestServer(t *testing.T, response proto.Message) (*httptest.Server, *[]*http.Request) {
+	t.Helper()
+
+	body, err := proto.Marshal(response)
+	require.NoError(t, err)
+
+	var requests []*http.Request
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+func TestClient_requests(t *testing.T) {
+	t.Run("hashLists", func(t *testing.T) {
+		srv, requests := newTestServer(t, &codegen.ListHashListsResponse{HashLists: []*codegen.HashList{{Name: "se"}}})
+
+		client, err := NewClient("test-key", WithBaseURL(srv.URL+"/"))
+		require.NoError(t, err)
+
+		result, body, err := client.V5alpha1HashLists(context.Background())
+		require.NoError(t, err)
+		require.NotEmpty(t, body)
+		require.Len(t, result.GetHashLists(), 1)
+		require.Equal(t, "se", result.GetHashLists()[0].GetName())
+
+		require.Len(t, *requests, 1)
+		require.Equal(t, "/v5alpha1/hashLists", (*requests)[0].URL.Path)
+		require.Empty(t, (*requests)[0].URL.Query())
+		require.Equal(t, "test-key", (*requests)[0].Header.Get("X-Goog-Api-Key"))
+	})
+
+	t.Run("hashLists:batchGet", func(t *testing.T) {
+		srv, requests := newTestServer(t, &codegen.ListHashListsResponse{})
+
+		client, err := NewClient("test-key", WithBaseURL(srv.URL))
+		require.NoError(t, err)
+
+		_, _, err = client.V5alpha1HashListsBatchGet(context.Background(), []string{"se", "mw"}, [][]byte{{1, 2}, nil})
+		require.NoError(t, err)
+
+		require.Len(t, *requests, 1)
+		require.Equal(t, "/v5alpha1/hashLists:batchGet", (*requests)[0].URL.Path)
+		require.Equal(t, url.Values{
+			"names":   {"se", "mw"},
+			"version": {"AQI=", ""},
+		}, (*requests)[0].URL.Query())
+	})
+
+	t.Run("hashes:search", func(t *testing.T) {
+		fullHash := sha256.Sum256([]byte("example.com/"))
+		srv, requests := newTestServer(t, &codegen.SearchHashesResponse{
+			FullHashes: []*codegen.FullHash{{FullHash: fullHash[:]}},
+		})
+
+		client, err := NewClient("test-key", WithBaseURL(srv.URL))
+		require.NoError(t, err)
+
+		result, _, err := client.V5alpha1HashesSearch(context.Background(), [][]byte{fullHash[:4]})
+		require.NoError(t, err)
+		require.Len(t, result.GetFullHashes(), 1)
+		require.Equal(t, fullHash[:], result.GetFullHashes()[0].GetFullHash())
+
+		require.Len(t, *requests, 1)
+		require.Equal(t, "/v5alpha1/hashes:search", (*requests)[0].URL.Path)
+		require.Equal(t, "test-key", (*requests)[0].Header.Get("X-Goog-Api-Key"))
+		require.Equal(t, []string{base64.StdEncoding.EncodeToString(fullHash[:4])}, (*requests)[0].URL.Query()["hashPrefixes"])
+	})
+}
+
+func TestClient_timeout(t *testing.T) {
+	unblock := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-unblock:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(unblock) })
+
+	client, err := NewClient("test-key", WithBaseURL(srv.URL), WithTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+
+	_, _, err = client.V5alpha1HashLists(context.Background())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Too many requests", http.StatusTooManyRequests)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient("test-key", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	_, _, err = client.V5alpha1HashLists(context.Background())
+
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	require.Equal(t, "Too many requests\n", string(apiErr.Body))
+	require.True(t, IsRetryable(err))
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "bad request", err: &Error{StatusCode: http.StatusBadRequest}, want: false},
+		{name: "forbidden", err: &Error{StatusCode: http.StatusForbidden}, want: false},
+		{name: "not found", err: &Error{StatusCode: http.StatusNotFound}, want: false},
+		{name: "too many requests", err: &Error{StatusCode: http.StatusTooManyRequests}, want: true},
+		{name: "internal server error", err: &Error{StatusCode: http.StatusInternalServerError}, want: true},
+		{name: "service unavailable", err: &Error{StatusCode: http.StatusServiceUnavailable}, want: true},
+		{name: "wrapped", err: fmt.Errorf("fetching lists: %w", &Error{StatusCode: http.StatusBadGateway}), want: true},
+		{name: "network", err: errors.New("connection refused"), want: true},
+		{name: "deadline", err: context.DeadlineExceeded, want: true},
+		{name: "cancelled", err: context.Canceled, want: false},
+		{name: "nil", err: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
+
+// countingTransport counts the requests and the new connections of the wrapped transport.
+type countingTransport struct {
+	transport   http.RoundTripper
+	requests    atomic.Int32
+	connections atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+	return t.transport.RoundTrip(req)
+}
+
+func TestClient_httpClient(t *testing.T) {
+	srv, _ := newTestServer(t, &codegen.ListHashListsResponse{})
+
+	transport := &countingTransport{}
+	defaultTransport := http.DefaultTransport.(*http.Transport).Clone()
+	dialContext := defaultTransport.DialContext
+	defaultTransport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		transport.connections.Add(1)
+		return dialContext(ctx, network, addr)
+	}
+	transport.transport = defaultTransport
+	t.Cleanup(defaultTransport.CloseIdleConnections)
+
+	client, err := NewClient("test-key", WithBaseURL(srv.URL), WithHTTPClient(&http.Client{Transport: transport}))
+	require.NoError(t, err)
+
+	for range 3 {
+		_, _, err = client.V5alpha1HashLists(context.Background())
+		require.NoError(t, err)
+	}
+
+	require.EqualValues(t, 3, transport.requests.Load())
+	require.EqualValues(t, 1, transport.connections.Load(), "connections are reused")
+}
+
+func writeFile(t *testing.T, name string, data []byte) {
+	t.Helper()
+
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o777)
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = io.Copy(f, bytes.NewReader(data))
+	require.NoError(t, err)
+}
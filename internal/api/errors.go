@@ -0,0 +1,340 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// maxErrorBodySize limits how much of an error response is read. Error bodies are short messages, anything longer is
+// not worth keeping.
+const maxErrorBodySize = 4 << 10
+
+// errorInfoType is the type URL of the google.rpc.ErrorInfo details, which tell the reason of an error.
+const errorInfoType = "type.googleapis.com/google.rpc.ErrorInfo"
+
+var (
+	// ErrInvalidAPIKey is matched by the errors of requests with a missing, invalid, revoked or expired API key.
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	// ErrQuotaExceeded is matched by the errors of rate limited requests.
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrPermissionDenied is matched by the errors of requests the key is not allowed to make, e.g. when the API is
+	// not enabled for the project of the key.
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrUnavailable is matched by the errors of requests the API failed to serve.
+	ErrUnavailable = errors.New("service unavailable")
+)
+
+// Error is a response of the API with a status other than 200 OK. The API describes the error with a google.rpc.Status
+// in the body, which is decoded from JSON or protobuf. Match it with errors.Is against ErrInvalidAPIKey,
+// ErrQuotaExceeded, ErrPermissionDenied and ErrUnavailable.
+type Error struct {
+	// URL is the requested URL. It doesn't include the API key, which is sent in a header.
+	URL        string
+	StatusCode int
+	// Status is the name of the google.rpc.Code of the error, e.g. "PERMISSION_DENIED".
+	Status  string
+	Message string
+	Details []ErrorDetail
+	// Body is the raw response, kept for the errors which are not a google.rpc.Status. It's not a part of the error
+	// message, because a proxy or a misbehaving server may echo the request, API key included.
+	Body []byte
+}
+
+// ErrorDetail is a detail of a google.rpc.Status. The reason, domain and metadata are only set for a
+// google.rpc.ErrorInfo, other details are left undecoded.
+type ErrorDetail struct {
+	// Type is the type URL of the detail, e.g. "type.googleapis.com/google.rpc.ErrorInfo".
+	Type string
+	// Reason is the cause of the error, e.g. "API_KEY_INVALID".
+	Reason   string
+	Domain   string
+	Metadata map[string]string
+}
+
+// newError creates the error of the response. A body which is not a google.rpc.Status is only kept as is.
+func newError(rawURL string, resp *http.Response, body []byte) *Error {
+	e := &Error{
+		URL:        rawURL,
+		StatusCode: resp.StatusCode,
+		Body:       body,
+	}
+
+	if strings.Contains(resp.Header.Get("Content-Type"), "json") || bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		_ = e.unmarshalJSON(body)
+	} else {
+		_ = e.unmarshalProto(body)
+	}
+
+	return e
+}
+
+// Error describes the error by the decoded google.rpc.Status only, the raw body is left out.
+func (e *Error) Error() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "failed request %s: status=%d", e.URL, e.StatusCode)
+
+	if e.Status != "" {
+		fmt.Fprintf(&sb, " %s", e.Status)
+	}
+
+	if reason := e.Reason(); reason != "" {
+		fmt.Fprintf(&sb, " (%s)", reason)
+	}
+
+	if e.Message != "" {
+		fmt.Fprintf(&sb, ": %s", e.Message)
+	}
+
+	return sb.String()
+}
+
+// Reason returns the reason of the first google.rpc.ErrorInfo detail, or an empty string without one.
+func (e *Error) Reason() string {
+	for _, detail := range e.Details {
+		if detail.Reason != "" {
+			return detail.Reason
+		}
+	}
+
+	return ""
+}
+
+// Is matches the error against the sentinel errors of the package.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrInvalidAPIKey:
+		return slices.Contains([]string{"API_KEY_INVALID", "API_KEY_EXPIRED"}, e.Reason())
+	case ErrQuotaExceeded:
+		return e.StatusCode == http.StatusTooManyRequests || e.Status == "RESOURCE_EXHAUSTED"
+	case ErrPermissionDenied:
+		return e.StatusCode == http.StatusForbidden || e.Status == "PERMISSION_DENIED"
+	case ErrUnavailable:
+		return e.StatusCode >= http.StatusInternalServerError || e.Status == "UNAVAILABLE"
+	}
+
+	return false
+}
+
+// Retryable reports whether the same request may succeed later: the API is rate limited or temporarily unavailable.
+// Other statuses, e.g. 400 or 403 for an invalid key, fail until the request or the configuration is fixed.
+func (e *Error) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// IsRetryable reports whether the request which failed with err may succeed later. Errors other than API responses,
+// e.g. network failures and timeouts, are retryable, except for a cancelled context.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+
+	return true
+}
+
+// unmarshalJSON decodes the JSON form of google.rpc.Status, which is wrapped in an "error" object. Its code is the
+// HTTP status, the name of the google.rpc.Code is in the status field.
+func (e *Error) unmarshalJSON(body []byte) error {
+	var response struct {
+		Error struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+			Details []struct {
+				Type     string            `json:"@type"`
+				Reason   string            `json:"reason"`
+				Domain   string            `json:"domain"`
+				Metadata map[string]string `json:"metadata"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return err
+	}
+
+	e.Status = response.Error.Status
+	e.Message = response.Error.Message
+
+	for _, detail := range response.Error.Details {
+		e.Details = append(e.Details, ErrorDetail{
+			Type:     detail.Type,
+			Reason:   detail.Reason,
+			Domain:   detail.Domain,
+			Metadata: detail.Metadata,
+		})
+	}
+
+	return nil
+}
+
+// codeNames are the names of google.rpc.Code values.
+var codeNames = []string{
+	"OK",
+	"CANCELLED",
+	"UNKNOWN",
+	"INVALID_ARGUMENT",
+	"DEADLINE_EXCEEDED",
+	"NOT_FOUND",
+	"ALREADY_EXISTS",
+	"PERMISSION_DENIED",
+	"RESOURCE_EXHAUSTED",
+	"FAILED_PRECONDITION",
+	"ABORTED",
+	"OUT_OF_RANGE",
+	"UNIMPLEMENTED",
+	"INTERNAL",
+	"UNAVAILABLE",
+	"DATA_LOSS",
+	"UNAUTHENTICATED",
+}
+
+// unmarshalProto decodes the protobuf form of google.rpc.Status:
+//
+//	message Status { int32 code = 1; string message = 2; repeated google.protobuf.Any details = 3; }
+//
+// The message is not a part of the generated protos, so it's decoded field by field.
+func (e *Error) unmarshalProto(body []byte) error {
+	var (
+		code    uint64
+		message string
+		details []ErrorDetail
+	)
+
+	err := consumeFields(body, func(num protowire.Number, value []byte) error {
+		switch num {
+		case 1:
+			code, _ = protowire.ConsumeVarint(value)
+		case 2:
+			message = string(value)
+		case 3:
+			detail, err := unmarshalAnyDetail(value)
+			if err != nil {
+				return err
+			}
+			details = append(details, detail)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Zero is also the value of a missing code, and OK is not an error anyway.
+	if code > 0 && code < uint64(len(codeNames)) {
+		e.Status = codeNames[code]
+	}
+	e.Message = message
+	e.Details = details
+
+	return nil
+}
+
+// unmarshalAnyDetail decodes a google.protobuf.Any detail and the google.rpc.ErrorInfo it may hold:
+//
+//	message Any { string type_url = 1; bytes value = 2; }
+//	message ErrorInfo { string reason = 1; string domain = 2; map<string, string> metadata = 3; }
+func unmarshalAnyDetail(data []byte) (ErrorDetail, error) {
+	var (
+		detail ErrorDetail
+		value  []byte
+	)
+
+	err := consumeFields(data, func(num protowire.Number, field []byte) error {
+		switch num {
+		case 1:
+			detail.Type = string(field)
+		case 2:
+			value = field
+		}
+		return nil
+	})
+	if err != nil || detail.Type != errorInfoType {
+		return detail, err
+	}
+
+	err = consumeFields(value, func(num protowire.Number, field []byte) error {
+		switch num {
+		case 1:
+			detail.Reason = string(field)
+		case 2:
+			detail.Domain = string(field)
+		case 3:
+			var key, value string
+
+			err := consumeFields(field, func(num protowire.Number, entry []byte) error {
+				switch num {
+				case 1:
+					key = string(entry)
+				case 2:
+					value = string(entry)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if detail.Metadata == nil {
+				detail.Metadata = make(map[string]string)
+			}
+			detail.Metadata[key] = value
+		}
+		return nil
+	})
+
+	return detail, err
+}
+
+// consumeFields calls fn with the value of every field of the message. Varint fields are passed encoded, length
+// delimited fields without the length. Other wire types are not used by the decoded messages and are skipped.
+func consumeFields(data []byte, fn func(num protowire.Number, value []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		var value []byte
+
+		switch typ {
+		case protowire.VarintType:
+			_, n = protowire.ConsumeVarint(data)
+			if n >= 0 {
+				value = data[:n]
+			}
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			data = data[n:]
+			continue
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		if err := fn(num, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const invalidKeyJSON = `{
+  "error": {
+    "code": 400,
+    "message": "API key not valid. Please pass a valid API key.",
+    "status": "INVALID_ARGUMENT",
+    "details": [
+      {
+        "@type": "type.googleapis.com/google.rpc.ErrorInfo",
+        "reason": "API_KEY_INVALID",
+        "domain": "googleapis.com",
+        "metadata": {
+          "service": "safebrowsing.googleapis.com"
+        }
+      },
+      {
+        "@type": "type.googleapis.com/google.rpc.LocalizedMessage",
+        "locale": "en-US",
+        "message": "API key not valid. Please pass a valid API key."
+      }
+    ]
+  }
+}`
+
+// statusProto encodes a google.rpc.Status with a google.rpc.ErrorInfo detail.
+func statusProto(code uint64, message, reason string, metadata map[string]string) []byte {
+	var errorInfo []byte
+	errorInfo = protowire.AppendTag(errorInfo, 1, protowire.BytesType)
+	errorInfo = protowire.AppendString(errorInfo, reason)
+	errorInfo = protowire.AppendTag(errorInfo, 2, protowire.BytesType)
+	errorInfo = protowire.AppendString(errorInfo, "googleapis.com")
+	for key, value := range metadata {
+		var entry []byte
+		entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+		entry = protowire.AppendString(entry, key)
+		entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+		entry = protowire.AppendString(entry, value)
+
+		errorInfo = protowire.AppendTag(errorInfo, 3, protowire.BytesType)
+		errorInfo = protowire.AppendBytes(errorInfo, entry)
+	}
+
+	var detail []byte
+	detail = protowire.AppendTag(detail, 1, protowire.BytesType)
+	detail = protowire.AppendString(detail, errorInfoType)
+	detail = protowire.AppendTag(detail, 2, protowire.BytesType)
+	detail = protowire.AppendBytes(detail, errorInfo)
+
+	var status []byte
+	status = protowire.AppendTag(status, 1, protowire.VarintType)
+	status = protowire.AppendVarint(status, code)
+	status = protowire.AppendTag(status, 2, protowire.BytesType)
+	status = protowire.AppendString(status, message)
+	// An unknown fixed size field is skipped.
+	status = protowire.AppendTag(status, 4, protowire.Fixed32Type)
+	status = protowire.AppendFixed32(status, 42)
+	status = protowire.AppendTag(status, 3, protowire.BytesType)
+	status = protowire.AppendBytes(status, detail)
+
+	return status
+}
+
+func TestClient_errorStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		contentType string
+		body        []byte
+		want        *Error
+		is          []error
+		isNot       []error
+	}{
+		{
+			name:        "invalid key in JSON",
+			statusCode:  http.StatusBadRequest,
+			contentType: "application/json; charset=UTF-8",
+			body:        []byte(invalidKeyJSON),
+			want: &Error{
+				StatusCode: http.StatusBadRequest,
+				Status:     "INVALID_ARGUMENT",
+				Message:    "API key not valid. Please pass a valid API key.",
+				Details: []ErrorDetail{
+					{
+						Type:     "type.googleapis.com/google.rpc.ErrorInfo",
+						Reason:   "API_KEY_INVALID",
+						Domain:   "googleapis.com",
+						Metadata: map[string]string{"service": "safebrowsing.googleapis.com"},
+					},
+					{Type: "type.googleapis.com/google.rpc.LocalizedMessage"},
+				},
+			},
+			is:    []error{ErrInvalidAPIKey},
+			isNot: []error{ErrQuotaExceeded, ErrPermissionDenied, ErrUnavailable},
+		},
+		{
+			name:        "quota exceeded in protobuf",
+			statusCode:  http.StatusTooManyRequests,
+			contentType: "application/x-protobuf",
+			body:        statusProto(8, "Quota exceeded.", "RATE_LIMIT_EXCEEDED", map[string]string{"quota_limit": "default"}),
+			want: &Error{
+				StatusCode: http.StatusTooManyRequests,
+				Status:     "RESOURCE_EXHAUSTED",
+				Message:    "Quota exceeded.",
+				Details: []ErrorDetail{
+					{
+						Type:     errorInfoType,
+						Reason:   "RATE_LIMIT_EXCEEDED",
+						Domain:   "googleapis.com",
+						Metadata: map[string]string{"quota_limit": "default"},
+					},
+				},
+			},
+			is:    []error{ErrQuotaExceeded},
+			isNot: []error{ErrInvalidAPIKey, ErrPermissionDenied, ErrUnavailable},
+		},
+		{
+			name:        "API disabled in protobuf",
+			statusCode:  http.StatusForbidden,
+			contentType: "application/x-protobuf",
+			body:        statusProto(7, "Safe Browsing API has not been used in project 1.", "SERVICE_DISABLED", nil),
+			want: &Error{
+				StatusCode: http.StatusForbidden,
+				Status:     "PERMISSION_DENIED",
+				Message:    "Safe Browsing API has not been used in project 1.",
+				Details:    []ErrorDetail{{Type: errorInfoType, Reason: "SERVICE_DISABLED", Domain: "googleapis.com"}},
+			},
+			is:    []error{ErrPermissionDenied},
+			isNot: []error{ErrInvalidAPIKey, ErrQuotaExceeded, ErrUnavailable},
+		},
+		{
+			name:        "unavailable without a status, echoing the key",
+			statusCode:  http.StatusBadGateway,
+			contentType: "text/html",
+			body:        []byte("<html>Bad Gateway, key=secret-key</html>"),
+			want: &Error{
+				StatusCode: http.StatusBadGateway,
+				Body:       []byte("<html>Bad Gateway, key=secret-key</html>"),
+			},
+			is:    []error{ErrUnavailable},
+			isNot: []error{ErrInvalidAPIKey, ErrQuotaExceeded, ErrPermissionDenied},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write(tt.body)
+			}))
+			t.Cleanup(srv.Close)
+
+			client, err := NewClient("secret-key", WithBaseURL(srv.URL))
+			require.NoError(t, err)
+
+			_, _, err = client.V5alpha1HashLists(context.Background())
+
+			var apiErr *Error
+			require.ErrorAs(t, err, &apiErr)
+
+			tt.want.URL = srv.URL + "/v5alpha1/hashLists"
+			tt.want.Body = tt.body
+			assert.Equal(t, tt.want, apiErr)
+
+			for _, target := range tt.is {
+				assert.ErrorIs(t, err, target)
+			}
+			for _, target := range tt.isNot {
+				assert.NotErrorIs(t, err, target)
+			}
+
+			assert.NotContains(t, err.Error(), "secret-key")
+		})
+	}
+}
+
+func TestError_Error(t *testing.T) {
+	err := &Error{
+		URL:        "https://safebrowsing.googleapis.com/v5alpha1/hashLists",
+		StatusCode: http.StatusBadRequest,
+		Status:     "INVALID_ARGUMENT",
+		Message:    "API key not valid.",
+		Details:    []ErrorDetail{{Type: errorInfoType, Reason: "API_KEY_INVALID"}},
+	}
+
+	assert.Equal(t,
+		"failed request https://safebrowsing.googleapis.com/v5alpha1/hashLists: status=400 INVALID_ARGUMENT (API_KEY_INVALID): API key not valid.",
+		err.Error(),
+	)
+
+	// A body which is not a google.rpc.Status may echo the request, so it's left out
+	err = &Error{URL: "http://localhost/", StatusCode: http.StatusBadGateway, Body: []byte("Bad Gateway: X-Goog-Api-Key=secret-key\n")}
+
+	assert.Equal(t, "failed request http://localhost/: status=502", err.Error())
+}
+
+func TestClient_networkError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	client, err := NewClient("secret-key", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	_, _, err = client.V5alpha1HashLists(context.Background())
+
+	var urlErr *url.Error
+	require.True(t, errors.As(err, &urlErr))
+	assert.NotContains(t, err.Error(), "secret-key")
+	assert.True(t, IsRetryable(err))
+}
@@ -0,0 +1,91 @@
+// Package backoff implements the back-off mode of the Safe Browsing error handling.
+package backoff
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+const (
+	// minInterval is the shortest wait after the first failure. The wait is randomized up to twice as long, so
+	// clients which failed at the same time don't retry at the same time.
+	minInterval = 15 * time.Minute
+	// maxInterval caps the wait after any number of failures.
+	maxInterval = 24 * time.Hour
+)
+
+// Backoff tracks the consecutive failed requests: after the n-th failure in a row the client waits
+// MIN_WAIT_DURATION * 2^(n-1) * (1 + RAND) before the next request. It's safe for concurrent use.
+type Backoff struct {
+	lock     sync.Mutex
+	failures int
+	// retryAt is when the next request may be made.
+	retryAt time.Time
+	// random returns a number in [0, 1). It's replaceable for tests.
+	random func() float64
+}
+
+func New() *Backoff {
+	return &Backoff{random: rand.Float64}
+}
+
+// Fail records a failure and returns how long to wait before the next request.
+func (b *Backoff) Fail() time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.failures++
+
+	interval := time.Duration(float64(minInterval) * (1 + b.random()))
+
+	// Past the cap, the interval doesn't need to be doubled anymore, which also keeps it from overflowing.
+	for range b.failures - 1 {
+		if interval >= maxInterval {
+			break
+		}
+		interval *= 2
+	}
+
+	return b.wait(min(interval, maxInterval))
+}
+
+// GiveUp records a failure which won't be fixed by retrying, e.g. an invalid API key, and returns the longest wait.
+func (b *Backoff) GiveUp() time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.failures++
+
+	return b.wait(maxInterval)
+}
+
+func (b *Backoff) wait(interval time.Duration) time.Duration {
+	b.retryAt = time.Now().Add(interval)
+	return interval
+}
+
+// Reset leaves the back-off mode after a successful request.
+func (b *Backoff) Reset() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.failures = 0
+	b.retryAt = time.Time{}
+}
+
+// Remaining returns how long to wait until the next request may be made, zero when the back-off mode is off.
+func (b *Backoff) Remaining() time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return max(time.Until(b.retryAt), 0)
+}
+
+// Failures returns the number of failures in a row.
+func (b *Backoff) Failures() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.failures
+}
@@ -0,0 +1,67 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_backoff(t *testing.T) {
+	t.Run("doubles after each failure", func(t *testing.T) {
+		b := &Backoff{random: func() float64 { return 0 }}
+
+		intervals := make([]time.Duration, 0, 9)
+		for range cap(intervals) {
+			intervals = append(intervals, b.Fail())
+		}
+
+		assert.Equal(t, []time.Duration{
+			15 * time.Minute,
+			30 * time.Minute,
+			time.Hour,
+			2 * time.Hour,
+			4 * time.Hour,
+			8 * time.Hour,
+			16 * time.Hour,
+			24 * time.Hour,
+			24 * time.Hour,
+		}, intervals)
+	})
+
+	t.Run("randomized up to twice as long", func(t *testing.T) {
+		b := &Backoff{random: func() float64 { return 0.5 }}
+
+		assert.Equal(t, 22*time.Minute+30*time.Second, b.Fail())
+		assert.Equal(t, 45*time.Minute, b.Fail())
+	})
+
+	t.Run("random intervals are in range", func(t *testing.T) {
+		b := New()
+
+		for failure := 1; failure <= 5; failure++ {
+			minInterval := minInterval << (failure - 1)
+			assert.GreaterOrEqual(t, b.Fail(), minInterval)
+		}
+
+		for range 100 {
+			assert.LessOrEqual(t, b.Fail(), maxInterval)
+		}
+	})
+
+	t.Run("reset after success", func(t *testing.T) {
+		b := &Backoff{random: func() float64 { return 0 }}
+
+		b.Fail()
+		b.Fail()
+		b.Reset()
+
+		assert.Equal(t, 15*time.Minute, b.Fail())
+	})
+
+	t.Run("give up", func(t *testing.T) {
+		b := &Backoff{random: func() float64 { return 0 }}
+
+		assert.Equal(t, maxInterval, b.GiveUp())
+	})
+}
@@ -0,0 +1,735 @@
+// Package database keeps the local copy of the Safe Browsing hash lists and looks hashes up in it.
+package database
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JILeXanDR/gsb-v5-tests/internal/api"
+	"github.com/JILeXanDR/gsb-v5-tests/internal/backoff"
+	"github.com/JILeXanDR/gsb-v5-tests/internal/logging"
+	"github.com/JILeXanDR/gsb-v5-tests/internal/rice"
+	"github.com/JILeXanDR/gsb-v5-tests/proto"
+)
+
+// Hardcode available lists like in docs says https://developers.google.com/safe-browsing/reference#available-lists.
+var recommendedLists = []*proto.HashList{
+	{
+		Name: "gc",
+		Metadata: &proto.HashListMetadata{
+			LikelySafeTypes: []proto.LikelySafeType{proto.LikelySafeType_GENERAL_BROWSING},
+		},
+	},
+	{
+		Name: "se",
+		Metadata: &proto.HashListMetadata{
+			ThreatTypes: []proto.ThreatType{proto.ThreatType_SOCIAL_ENGINEERING},
+		},
+	},
+	{
+		Name: "mw",
+		Metadata: &proto.HashListMetadata{
+			ThreatTypes: []proto.ThreatType{proto.ThreatType_MALWARE},
+		},
+	},
+	{
+		Name: "uws",
+		Metadata: &proto.HashListMetadata{
+			ThreatTypes: []proto.ThreatType{proto.ThreatType_UNWANTED_SOFTWARE},
+		},
+	},
+	{
+		Name: "uwsa",
+		Metadata: &proto.HashListMetadata{
+			ThreatTypes: []proto.ThreatType{proto.ThreatType_UNWANTED_SOFTWARE},
+		},
+	},
+	{
+		Name: "pha",
+		Metadata: &proto.HashListMetadata{
+			ThreatTypes: []proto.ThreatType{proto.ThreatType_POTENTIALLY_HARMFUL_APPLICATION},
+		},
+	},
+}
+
+// Database is the local copy of the hash lists. It's kept up to date by RunSelfUpdates.
+type Database struct {
+	api api.API
+	// path is where the database snapshot is stored. The database lives only in memory when it's empty.
+	path string
+	// mapping is the memory-mapped snapshot which the prefixes of the loaded lists refer to.
+	mapping *mappedFile
+
+	lists      []localList
+	lastUpdate time.Time
+
+	logger logging.Logger
+
+	// backoff counts the failed requests in a row, no update is made until it's over. It's shared with the hash
+	// searches, see Backoff.
+	backoff *backoff.Backoff
+
+	lock *sync.RWMutex
+}
+
+func newDatabase(api api.API, path string) *Database {
+	return &Database{
+		api:     api,
+		path:    path,
+		lists:   make([]localList, 0),
+		logger:  logging.Default(),
+		backoff: backoff.New(),
+		lock:    &sync.RWMutex{},
+	}
+}
+
+// Open creates the database, which logs through logger, and brings it up to date. With a path, the database is
+// persisted to the file: it's loaded from it, so the lists are not downloaded again if they're not older than maxAge,
+// and saved after each update.
+//
+// When the update of a loaded database fails with a retryable error, the stale database is used, and RunSelfUpdates
+// retries in the back-off mode. So restarts while the API is unavailable don't retry too often. Invalid data in the
+// response doesn't discard the stale database either.
+func Open(ctx context.Context, client api.API, logger logging.Logger, path string, maxAge time.Duration) (*Database, error) {
+	d := newDatabase(client, path)
+	d.logger = logger
+
+	if path != "" {
+		if err := d.load(); err != nil {
+			d.logger.Printf("loading local database failed, starting from scratch: %+v", err)
+		}
+
+		if d.isFresh(maxAge) {
+			return d, nil
+		}
+	}
+
+	if err := d.update(ctx); err != nil {
+		if len(d.lists) > 0 && (api.IsRetryable(err) || !isRequestError(err)) {
+			wait := d.failed(err)
+			d.logger.Printf("updating loaded local database failed, using it as is and retrying in %s: %+v", wait, err)
+			return d, nil
+		}
+
+		d.Close()
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// RunSelfUpdates fetches every list once its minimum wait duration has passed. Lists which are due at the same time
+// are fetched in a single request.
+func (d *Database) RunSelfUpdates(ctx context.Context) {
+	timer := time.NewTimer(d.untilNextUpdate())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			// The back-off mode may have been entered by a failed hash search since the timer was set
+			if remaining := d.backoff.Remaining(); remaining > 0 {
+				timer.Reset(remaining)
+				continue
+			}
+
+			if err := d.update(ctx); err != nil {
+				// The update was interrupted, it didn't fail
+				if ctx.Err() != nil {
+					return
+				}
+
+				wait := d.failed(err)
+				d.logger.Printf("updating failed, retrying in %s: %+v", wait, err)
+				timer.Reset(wait)
+				continue
+			}
+
+			d.backoff.Reset()
+
+			timer.Reset(d.untilNextUpdate())
+		}
+	}
+}
+
+// Backoff returns the back-off state of the client. Other requests to the API share it, so the client backs off
+// whichever request failed.
+func (d *Database) Backoff() *backoff.Backoff {
+	return d.backoff
+}
+
+// failed returns how long to wait before the next update after a failed one. Only unsuccessful requests enter the
+// back-off mode, errors which retrying won't fix, e.g. an invalid API key, wait the longest. Invalid data in a
+// response is a problem of the lists, not of the API, so the lists are retried on their own schedule and hash
+// searches are not held back.
+func (d *Database) failed(err error) time.Duration {
+	if !isRequestError(err) {
+		return max(d.untilNextUpdate(), invalidDataRetryInterval)
+	}
+
+	if api.IsRetryable(err) {
+		return d.backoff.Fail()
+	}
+
+	return d.backoff.GiveUp()
+}
+
+// invalidDataRetryInterval is the shortest wait after an update which failed with invalid data. The failed lists are
+// due immediately, either dropped or not updated, and requesting them again right away likely fails the same way.
+const invalidDataRetryInterval = 15 * time.Minute
+
+// requestError is a failed request to the API, as opposed to invalid data in a successful response.
+type requestError struct {
+	err error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+func isRequestError(err error) bool {
+	var reqErr *requestError
+	return errors.As(err, &reqErr)
+}
+
+// untilNextUpdate returns how long to wait until the earliest list is due. Lists which are not fetched yet are due
+// immediately, unless the back-off mode is on.
+func (d *Database) untilNextUpdate() time.Duration {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	if remaining := d.backoff.Remaining(); remaining > 0 {
+		return remaining
+	}
+
+	var nextUpdate time.Time
+
+	for _, list := range recommendedLists {
+		local := d.findList(list.Name)
+		if local == nil {
+			return 0
+		}
+
+		if nextUpdate.IsZero() || local.nextUpdate.Before(nextUpdate) {
+			nextUpdate = local.nextUpdate
+		}
+	}
+
+	return max(time.Until(nextUpdate), 0)
+}
+
+// update fetches the lists which are due and stores the database snapshot when any list was updated. A snapshot
+// replaced by another process sharing the path is loaded first, so only the lists which are still due are fetched.
+func (d *Database) update(ctx context.Context) error {
+	if err := d.reload(); err != nil {
+		d.logger.Printf("reloading local database failed: %+v", err)
+	}
+
+	d.lock.RLock()
+	lastUpdate := d.lastUpdate
+	d.lock.RUnlock()
+
+	err := d.fetchUpdates(ctx)
+
+	d.lock.RLock()
+	updated := d.lastUpdate != lastUpdate
+	d.lock.RUnlock()
+
+	// The saved snapshot is mapped back, so the updated prefixes don't stay on the heap.
+	if updated && d.path != "" {
+		if err := d.save(); err != nil {
+			d.logger.Printf("saving local database failed: %+v", err)
+		} else if err := d.load(); err != nil {
+			d.logger.Printf("loading saved local database failed: %+v", err)
+		}
+	}
+
+	return err
+}
+
+// Close drops the lists and releases the mapped snapshot.
+func (d *Database) Close() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.lists = nil
+
+	err := d.mapping.close()
+	d.mapping = nil
+
+	return err
+}
+
+func (d *Database) fetchUpdates(ctx context.Context) error {
+	d.logger.Printf("running local database updates...")
+
+	// loadHashLists := sync.OnceValue(func() []*proto.HashList {
+	// 	log.Printf("loading list names once...")
+	//
+	// 	hashListsResult, _, err := d.api.V5alpha1HashLists(ctx)
+	// 	if err != nil {
+	// 		return nil
+	// 	}
+	//
+	// 	log.Printf("got hash lists, count=%d", len(hashListsResult.HashLists))
+	//
+	// 	return hashListsResult.HashLists
+	// })
+	//
+	// hashLists := loadHashLists()
+
+	var (
+		listNames    []string
+		listVersions [][]byte
+		hashLists    []*proto.HashList
+	)
+
+	now := time.Now()
+
+	d.lock.RLock()
+	for _, list := range recommendedLists {
+		local := d.findList(list.Name)
+		if local != nil && now.Before(local.nextUpdate) {
+			continue
+		}
+
+		listNames = append(listNames, list.Name)
+		hashLists = append(hashLists, list)
+
+		if local != nil {
+			listVersions = append(listVersions, local.version)
+		} else {
+			listVersions = append(listVersions, nil)
+		}
+	}
+	d.lock.RUnlock()
+
+	if len(listNames) == 0 {
+		d.logger.Printf("no lists are due for update")
+		return nil
+	}
+
+	result, _, err := d.api.V5alpha1HashListsBatchGet(ctx, listNames, listVersions)
+	if err != nil {
+		return &requestError{err: err}
+	}
+
+	mismatches, err := d.updateLists(result, hashLists)
+	if err != nil {
+		return err
+	}
+
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	return d.refetchLists(ctx, mismatches)
+}
+
+// refetchLists fetches the lists which failed checksum verification from scratch. Their state is already dropped
+// by updateLists, so no versions are sent.
+func (d *Database) refetchLists(ctx context.Context, mismatches []*ChecksumMismatchError) error {
+	listNames := make([]string, len(mismatches))
+	hashLists := make([]*proto.HashList, len(mismatches))
+
+	for i, mismatch := range mismatches {
+		d.logger.Printf("list checksum verification failed, fetching the list from scratch: %v", mismatch)
+
+		index := slices.IndexFunc(recommendedLists, func(list *proto.HashList) bool {
+			return list.Name == mismatch.ListName
+		})
+
+		listNames[i] = mismatch.ListName
+		hashLists[i] = recommendedLists[index]
+	}
+
+	result, _, err := d.api.V5alpha1HashListsBatchGet(ctx, listNames, make([][]byte, len(listNames)))
+	if err != nil {
+		return &requestError{err: err}
+	}
+
+	mismatches, err = d.updateLists(result, hashLists)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, len(mismatches))
+	for i, mismatch := range mismatches {
+		errs[i] = mismatch
+	}
+
+	return errors.Join(errs...)
+}
+
+// HashLookup is what the local lists know about a full hash. A hash found in a threat list only means that the URL
+// is possibly unsafe, it must be confirmed with full hashes.
+type HashLookup struct {
+	LikelySafeTypes []proto.LikelySafeType
+	ThreatLists     []ListVersion
+}
+
+// ListVersion is a list with the version a hash was found in.
+type ListVersion struct {
+	Name    string
+	Version []byte
+}
+
+// LookupHashes looks all the hashes up in every list under a single read lock. The results are in the order of the
+// hashes.
+func (d *Database) LookupHashes(hashes [][sha256.Size]byte) []HashLookup {
+	lookups := make([]HashLookup, len(hashes))
+
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	for _, list := range d.lists {
+		if len(list.likelySafeTypes) == 0 && len(list.threatTypes) == 0 {
+			continue
+		}
+
+		for i, hash := range hashes {
+			if !list.hashes.contains(hash) {
+				continue
+			}
+
+			lookup := &lookups[i]
+
+			for _, likelySafeType := range list.likelySafeTypes {
+				if !slices.Contains(lookup.LikelySafeTypes, likelySafeType) {
+					lookup.LikelySafeTypes = append(lookup.LikelySafeTypes, likelySafeType)
+				}
+			}
+
+			if len(list.threatTypes) > 0 {
+				// The version is copied, because the list is replaced by the next update
+				lookup.ThreatLists = append(lookup.ThreatLists, ListVersion{
+					Name:    list.name,
+					Version: bytes.Clone(list.version),
+				})
+			}
+		}
+	}
+
+	return lookups
+}
+
+// updateLists applies the received hash lists and verifies their checksums. A list which fails the verification is
+// dropped from the database and returned as a mismatch, so it can be fetched again from scratch.
+//
+// The received lists are matched to the requested ones by name. Lists which were not requested are ignored, and
+// requested lists missing from the response fail the update after the received ones are applied, so they are
+// requested again later.
+func (d *Database) updateLists(result *proto.ListHashListsResponse, hashLists []*proto.HashList) (mismatches []*ChecksumMismatchError, err error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	lists := slices.Clone(d.lists)
+	received := make([]bool, len(hashLists))
+
+	for _, list := range result.HashLists {
+		i := slices.IndexFunc(hashLists, func(hashList *proto.HashList) bool {
+			return hashList.Name == list.Name
+		})
+		if i == -1 {
+			d.logger.Printf("ignoring list %q, it was not requested", list.Name)
+			continue
+		}
+		received[i] = true
+
+		hashList := hashLists[i]
+
+		var previous localList
+		if local := d.findList(hashList.Name); local != nil && list.PartialUpdate {
+			previous = *local
+		}
+
+		updated, err := d.buildLocalList(previous, list, hashList)
+		if err != nil {
+			return nil, err
+		}
+
+		index := slices.IndexFunc(lists, func(local localList) bool {
+			return local.name == updated.name
+		})
+
+		if checksum := updated.hashes.checksum(); updated.sha256Checksum != nil && !bytes.Equal(checksum, updated.sha256Checksum) {
+			mismatches = append(mismatches, &ChecksumMismatchError{
+				ListName: updated.name,
+				Version:  updated.version,
+				Expected: updated.sha256Checksum,
+				Actual:   checksum,
+			})
+
+			if index != -1 {
+				lists = slices.Delete(lists, index, index+1)
+			}
+
+			continue
+		}
+
+		if index == -1 {
+			lists = append(lists, updated)
+		} else {
+			lists[index] = updated
+		}
+
+		d.logger.Printf(
+			`updated local list "%s", partial=%v, entries=%d, threatTypes=%v, likelySafeTypes=%v, description=%s`,
+			updated.name,
+			list.PartialUpdate,
+			updated.entriesCount,
+			updated.threatTypes,
+			updated.likelySafeTypes,
+			updated.description,
+		)
+	}
+
+	d.lists = lists
+	d.lastUpdate = time.Now()
+
+	var missing []string
+	for i, hashList := range hashLists {
+		if !received[i] {
+			missing = append(missing, hashList.Name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return mismatches, fmt.Errorf("lists missing from the response: %s", strings.Join(missing, ", "))
+	}
+
+	return mismatches, nil
+}
+
+// isFresh reports whether the database was updated within maxAge, so it can be used without waiting for an update.
+func (d *Database) isFresh(maxAge time.Duration) bool {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	return len(d.lists) > 0 && time.Since(d.lastUpdate) <= maxAge
+}
+
+// findList must be called with the lock held.
+func (d *Database) findList(name string) *localList {
+	for i := range d.lists {
+		if d.lists[i].name == name {
+			return &d.lists[i]
+		}
+	}
+
+	return nil
+}
+
+// ChecksumMismatchError is reported when the checksum of a list after an update doesn't match the server one.
+type ChecksumMismatchError struct {
+	ListName string
+	Version  []byte
+	Expected []byte
+	Actual   []byte
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("checksum mismatch of list %s: version=%x, expected=%x, actual=%x", e.ListName, e.Version, e.Expected, e.Actual)
+}
+
+type localList struct {
+	name                 string
+	description          string
+	hashes               hashPrefixes
+	entriesCount         int32
+	threatTypes          []proto.ThreatType
+	likelySafeTypes      []proto.LikelySafeType
+	supportedHashLengths []proto.HashLength
+	version              []byte
+	sha256Checksum       []byte
+	nextUpdate           time.Time
+}
+
+// buildLocalList applies the hash list received from the server to the previous state of the list. The previous state
+// is empty when the list is fetched for the first time or the server sent the complete list.
+func (d *Database) buildLocalList(previous localList, list *proto.HashList, hashList *proto.HashList) (localList, error) {
+	name := hashList.Name
+
+	d.logger.Printf("decoding list hashes=%s", name)
+
+	var removals []uint32
+
+	if res := list.CompressedRemovals; res != nil {
+		d.logger.Printf("decode CompressedRemovals (RiceDeltaEncoded32Bit), first=%d entries=%d, rice=%d", res.FirstValue, res.EntriesCount, res.RiceParameter)
+
+		enc := &rice.Golomb32BitEncoding{
+			FirstValue:    res.FirstValue,
+			RiceParameter: uint32(res.RiceParameter),
+			EncodedData:   res.EncodedData,
+			EntryCount:    uint32(res.EntriesCount),
+		}
+
+		decodedIndices, err := enc.Decode()
+		if err != nil {
+			return localList{}, err
+		}
+
+		d.logger.Printf("CompressedRemovals (RiceDeltaEncoded32Bit) decoded: %d", len(decodedIndices))
+
+		removals = decodedIndices
+	}
+
+	additions, err := d.decodeAdditions(list)
+	if err != nil {
+		return localList{}, err
+	}
+
+	// Removal indices refer to the entries of the previous state, whichever hash length the list uses.
+	hashes, err := applyHashesDiff(previous.hashes, removals, additions)
+	if err != nil {
+		return localList{}, fmt.Errorf("apply diff to list %s: %w", name, err)
+	}
+
+	// A missing or zero wait duration means that the server has more updates, so the list is due immediately.
+	return localList{
+		name:                 name,
+		description:          hashList.Metadata.Description,
+		hashes:               hashes,
+		entriesCount:         int32(hashes.len()),
+		threatTypes:          hashList.Metadata.ThreatTypes,
+		likelySafeTypes:      hashList.Metadata.LikelySafeTypes,
+		supportedHashLengths: hashList.Metadata.SupportedHashLengths,
+		version:              list.Version,
+		sha256Checksum:       list.GetSha256Checksum(),
+		nextUpdate:           time.Now().Add(list.GetMinimumWaitDuration().AsDuration()),
+	}, nil
+}
+
+// decodeAdditions decodes the additions of any hash length into big-endian prefixes.
+func (d *Database) decodeAdditions(list *proto.HashList) (hashPrefixes, error) {
+	switch additions := list.CompressedAdditions.(type) {
+	case *proto.HashList_AdditionsFourBytes:
+		res := additions.AdditionsFourBytes
+
+		d.logger.Printf("decode AdditionsFourBytes (RiceDeltaEncoded32Bit), first=%d entries=%d, rice=%d", res.FirstValue, res.EntriesCount, res.RiceParameter)
+
+		enc := &rice.Golomb32BitEncoding{
+			FirstValue:    res.FirstValue,
+			RiceParameter: uint32(res.RiceParameter),
+			EncodedData:   res.EncodedData,
+			EntryCount:    uint32(res.EntriesCount),
+		}
+
+		decodedHashes, err := enc.Decode()
+		if err != nil {
+			return hashPrefixes{}, err
+		}
+
+		d.logger.Printf("AdditionsFourBytes (RiceDeltaEncoded32Bit) decoded: %d", len(decodedHashes))
+
+		prefixes := newHashPrefixes(4, len(decodedHashes))
+		for _, hash := range decodedHashes {
+			prefixes.data = binary.BigEndian.AppendUint32(prefixes.data, hash)
+		}
+
+		return prefixes, nil
+	case *proto.HashList_AdditionsEightBytes:
+		res := additions.AdditionsEightBytes
+
+		d.logger.Printf("decode AdditionsEightBytes (RiceDeltaEncoded64Bit), first=%d entries=%d, rice=%d", res.FirstValue, res.EntriesCount, res.RiceParameter)
+
+		enc := &rice.Golomb64BitEncoding{
+			FirstValue:    res.FirstValue,
+			RiceParameter: uint32(res.RiceParameter),
+			EncodedData:   res.EncodedData,
+			EntryCount:    uint32(res.EntriesCount),
+		}
+
+		decodedHashes, err := enc.Decode()
+		if err != nil {
+			return hashPrefixes{}, err
+		}
+
+		d.logger.Printf("AdditionsEightBytes (RiceDeltaEncoded64Bit) decoded: %d", len(decodedHashes))
+
+		prefixes := newHashPrefixes(8, len(decodedHashes))
+		for _, hash := range decodedHashes {
+			prefixes.data = binary.BigEndian.AppendUint64(prefixes.data, hash)
+		}
+
+		return prefixes, nil
+	case *proto.HashList_AdditionsSixteenBytes:
+		res := additions.AdditionsSixteenBytes
+
+		d.logger.Printf("decode AdditionsSixteenBytes (RiceDeltaEncoded128Bit), hi=%d lo=%d entries=%d, rice=%d", res.FirstValueHi, res.FirstValueLo, res.EntriesCount, res.RiceParameter)
+
+		enc := &rice.Golomb128BitEncoding{
+			FirstValueHi:  res.FirstValueHi,
+			FirstValueLo:  res.FirstValueLo,
+			RiceParameter: uint32(res.RiceParameter),
+			EncodedData:   res.EncodedData,
+			EntryCount:    uint32(res.EntriesCount),
+		}
+
+		decodedHashes, err := enc.Decode()
+		if err != nil {
+			return hashPrefixes{}, err
+		}
+
+		d.logger.Printf("AdditionsSixteenBytes (RiceDeltaEncoded128Bit) decoded: %d", len(decodedHashes))
+
+		prefixes := newHashPrefixes(16, len(decodedHashes))
+		for _, hash := range decodedHashes {
+			prefixes.data = hash.AppendBytes(prefixes.data)
+		}
+
+		return prefixes, nil
+	case *proto.HashList_AdditionsThirtyTwoBytes:
+		res := additions.AdditionsThirtyTwoBytes
+
+		d.logger.Printf(
+			"decode AdditionsThirtyTwoBytes (RiceDeltaEncoded256Bit), first1=%d first2=%d first3=%d first4=%d entries=%d, rice=%d",
+			res.FirstValueFirstPart,
+			res.FirstValueSecondPart,
+			res.FirstValueThirdPart,
+			res.FirstValueFourthPart,
+			res.EntriesCount,
+			res.RiceParameter,
+		)
+
+		enc := &rice.Golomb256BitEncoding{
+			FirstValuePart1: res.FirstValueFirstPart,
+			FirstValuePart2: res.FirstValueSecondPart,
+			FirstValuePart3: res.FirstValueThirdPart,
+			FirstValuePart4: res.FirstValueFourthPart,
+			RiceParameter:   uint32(res.RiceParameter),
+			EncodedData:     res.EncodedData,
+			EntryCount:      uint32(res.EntriesCount),
+		}
+
+		decodedHashes, err := enc.Decode()
+		if err != nil {
+			return hashPrefixes{}, err
+		}
+
+		d.logger.Printf("AdditionsThirtyTwoBytes (RiceDeltaEncoded256Bit) decoded: %d", len(decodedHashes))
+
+		prefixes := newHashPrefixes(32, len(decodedHashes))
+		for _, hash := range decodedHashes {
+			prefixes.data = hash.AppendBytes(prefixes.data)
+		}
+
+		return prefixes, nil
+	}
+
+	return hashPrefixes{}, nil
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+)
+
+// hashPrefixes is a sorted set of big-endian hash prefixes of the same length, stored back to back. All lists are
+// kept this way regardless of their hash length, so they are looked up, diffed and verified the same way.
+type hashPrefixes struct {
+	length int
+	data   []byte
+}
+
+func newHashPrefixes(length int, capacity int) hashPrefixes {
+	return hashPrefixes{
+		length: length,
+		data:   make([]byte, 0, length*capacity),
+	}
+}
+
+func (p hashPrefixes) len() int {
+	if p.length == 0 {
+		return 0
+	}
+
+	return len(p.data) / p.length
+}
+
+func (p hashPrefixes) at(i int) []byte {
+	return p.data[i*p.length : (i+1)*p.length]
+}
+
+func (p hashPrefixes) append(prefix []byte) hashPrefixes {
+	p.data = append(p.data, prefix...)
+	return p
+}
+
+// contains reports whether the prefix of the full hash is in the set.
+func (p hashPrefixes) contains(hash [sha256.Size]byte) bool {
+	if p.length == 0 || p.length > len(hash) {
+		return false
+	}
+
+	prefix := hash[:p.length]
+
+	index := sort.Search(p.len(), func(i int) bool {
+		return bytes.Compare(p.at(i), prefix) >= 0
+	})
+
+	return index < p.len() && bytes.Equal(p.at(index), prefix)
+}
+
+// checksum returns SHA-256 of the sorted prefixes, the same way the server calculates HashList.sha256Checksum.
+func (p hashPrefixes) checksum() []byte {
+	hash := sha256.Sum256(p.data)
+	return hash[:]
+}
+
+// applyHashesDiff removes the entries at the given indices from the sorted hashes and merges in the sorted additions.
+// The indices refer to the hashes before the additions are merged in. The given prefixes are never modified.
+func applyHashesDiff(hashes hashPrefixes, removals []uint32, additions hashPrefixes) (hashPrefixes, error) {
+	if hashes.len() == 0 {
+		hashes.length = additions.length
+	}
+
+	if additions.len() > 0 && additions.length != hashes.length {
+		return hashPrefixes{}, fmt.Errorf("hash length of additions %d doesn't match the list one %d", additions.length, hashes.length)
+	}
+
+	result := newHashPrefixes(hashes.length, hashes.len()-min(len(removals), hashes.len()))
+
+	var removal int
+
+	for i := 0; i < hashes.len(); i++ {
+		if removal < len(removals) && removals[removal] == uint32(i) {
+			removal++
+			continue
+		}
+
+		result = result.append(hashes.at(i))
+	}
+
+	if removal != len(removals) {
+		return hashPrefixes{}, fmt.Errorf("removal index %d is out of range, entries=%d", removals[removal], hashes.len())
+	}
+
+	if additions.len() == 0 {
+		return result, nil
+	}
+
+	merged := newHashPrefixes(hashes.length, result.len()+additions.len())
+
+	var i, j int
+
+	for i < result.len() && j < additions.len() {
+		if bytes.Compare(result.at(i), additions.at(j)) <= 0 {
+			merged = merged.append(result.at(i))
+			i++
+		} else {
+			merged = merged.append(additions.at(j))
+			j++
+		}
+	}
+
+	merged.data = append(merged.data, result.data[i*result.length:]...)
+	merged.data = append(merged.data, additions.data[j*additions.length:]...)
+
+	return merged, nil
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hashFull(input string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(input))
+}
+
+// newUint32HashPrefixes builds sorted 4-byte prefixes from the given values.
+func newUint32HashPrefixes(hashes ...uint32) hashPrefixes {
+	hashes = slices.Sorted(slices.Values(hashes))
+
+	prefixes := newHashPrefixes(4, len(hashes))
+	for _, hash := range hashes {
+		prefixes.data = binary.BigEndian.AppendUint32(prefixes.data, hash)
+	}
+
+	return prefixes
+}
+
+func Test_hashPrefixes_contains(t *testing.T) {
+	expressions := []string{"a.example.com/", "b.example.com/", "y.example.com/"}
+
+	for _, length := range []int{4, 8, 16, 32} {
+		prefixes := newHashPrefixes(length, len(expressions))
+
+		hashes := make([][sha256.Size]byte, len(expressions))
+		for i, expression := range expressions {
+			hashes[i] = hashFull(expression)
+		}
+		slices.SortFunc(hashes, func(a, b [sha256.Size]byte) int {
+			return slices.Compare(a[:], b[:])
+		})
+
+		for _, hash := range hashes {
+			prefixes = prefixes.append(hash[:length])
+		}
+
+		require.Equal(t, len(expressions), prefixes.len())
+
+		for _, expression := range expressions {
+			assert.True(t, prefixes.contains(hashFull(expression)), "length=%d expression=%s", length, expression)
+		}
+
+		assert.False(t, prefixes.contains(hashFull("c.example.com/")), "length=%d", length)
+	}
+
+	assert.False(t, hashPrefixes{}.contains(hashFull("a.example.com/")))
+}
+
+func Test_applyHashesDiff(t *testing.T) {
+	tests := []struct {
+		name      string
+		hashes    hashPrefixes
+		removals  []uint32
+		additions hashPrefixes
+		expected  hashPrefixes
+	}{
+		{
+			name:      "first update",
+			hashes:    hashPrefixes{},
+			additions: newUint32HashPrefixes(1, 5, 9),
+			expected:  newUint32HashPrefixes(1, 5, 9),
+		},
+		{
+			name:     "only removals",
+			hashes:   newUint32HashPrefixes(1, 5, 9),
+			removals: []uint32{0, 2},
+			expected: newUint32HashPrefixes(5),
+		},
+		{
+			name:      "removals and additions",
+			hashes:    newUint32HashPrefixes(1, 5, 9),
+			removals:  []uint32{1},
+			additions: newUint32HashPrefixes(0, 7, 10),
+			expected:  newUint32HashPrefixes(0, 1, 7, 9, 10),
+		},
+		{
+			name:     "remove everything",
+			hashes:   newUint32HashPrefixes(1, 5, 9),
+			removals: []uint32{0, 1, 2},
+			expected: newUint32HashPrefixes(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := applyHashesDiff(test.hashes, test.removals, test.additions)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected.length, result.length)
+			assert.Equal(t, test.expected.data, result.data)
+		})
+	}
+
+	t.Run("removal out of range", func(t *testing.T) {
+		_, err := applyHashesDiff(newUint32HashPrefixes(1, 5), []uint32{2}, hashPrefixes{})
+		require.Error(t, err)
+	})
+
+	t.Run("hash length mismatch", func(t *testing.T) {
+		additions := newHashPrefixes(8, 1).append(make([]byte, 8))
+
+		_, err := applyHashesDiff(newUint32HashPrefixes(1, 5), nil, additions)
+		require.Error(t, err)
+	})
+}
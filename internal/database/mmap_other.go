@@ -0,0 +1,43 @@
+//go:build !unix
+
+package database
+
+import (
+	"io"
+	"os"
+)
+
+// mappedFile holds the file contents in memory on platforms without mmap support.
+type mappedFile struct {
+	data []byte
+	// info identifies the read file, which stays read after the path is replaced.
+	info os.FileInfo
+}
+
+func mapFile(path string) (*mappedFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mappedFile{data: data, info: info}, nil
+}
+
+func (m *mappedFile) close() error {
+	if m != nil {
+		m.data = nil
+	}
+
+	return nil
+}
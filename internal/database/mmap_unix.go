@@ -0,0 +1,52 @@
+//go:build unix
+
+package database
+
+import (
+	"os"
+	"syscall"
+)
+
+// mappedFile is a read-only shared memory mapping of a file. Processes which map the same file share its pages
+// through the page cache instead of holding their own copies.
+type mappedFile struct {
+	data []byte
+	// info identifies the mapped file, which stays mapped after the path is replaced.
+	info os.FileInfo
+}
+
+func mapFile(path string) (*mappedFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() == 0 {
+		return &mappedFile{info: info}, nil
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+
+	return &mappedFile{data: data, info: info}, nil
+}
+
+// close unmaps the file. The data must not be accessed afterwards.
+func (m *mappedFile) close() error {
+	if m == nil || m.data == nil {
+		return nil
+	}
+
+	data := m.data
+	m.data = nil
+
+	return syscall.Munmap(data)
+}
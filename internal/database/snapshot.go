@@ -0,0 +1,316 @@
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/JILeXanDR/gsb-v5-tests/proto"
+	proto2 "google.golang.org/protobuf/proto"
+)
+
+// The snapshot stores every local list with its decoded prefixes, so the database can be restored without
+// downloading the lists again. All numbers are big-endian, byte strings are prefixed with their uint32 length:
+//
+//	magic         [4]byte "GSB5"
+//	formatVersion uint16
+//	lastUpdate    int64, unix nanoseconds
+//	listsCount    uint32
+//	lists         listsCount times:
+//	  name           string
+//	  version        bytes
+//	  sha256Checksum bytes
+//	  metadata       bytes, HashListMetadata protobuf
+//	  nextUpdate     int64, unix nanoseconds
+//	  hashLength     uint32
+//	  prefixes       bytes, sorted prefixes of hashLength bytes
+//
+// The format version is increased on any incompatible change, snapshots of other versions are not loaded.
+const (
+	snapshotMagic         = "GSB5"
+	snapshotFormatVersion = 1
+)
+
+var errSnapshotFormat = errors.New("unsupported snapshot format")
+
+// save atomically replaces the snapshot file with the current state of the database. The lock is held while the
+// snapshot is written, because the prefixes may refer to the mapping which is released by the next load.
+func (d *Database) save() error {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	lists := d.lists
+	lastUpdate := d.lastUpdate
+
+	f, err := os.CreateTemp(filepath.Dir(d.path), filepath.Base(d.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err := writeSnapshot(f, lists, lastUpdate); err != nil {
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(f.Name(), d.path); err != nil {
+		return err
+	}
+
+	d.logger.Printf("local database saved to %s, lists=%d", d.path, len(lists))
+
+	return nil
+}
+
+// load restores the database from the snapshot file. The file is memory-mapped and the prefixes of the lists are
+// searched in place, so they are not copied to the heap. The previous mapping is released.
+func (d *Database) load() error {
+	mapping, err := mapFile(d.path)
+	if err != nil {
+		return err
+	}
+
+	lists, lastUpdate, err := readSnapshot(mapping.data)
+	if err != nil {
+		mapping.close()
+		return fmt.Errorf("read snapshot %s: %w", d.path, err)
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.lists = lists
+	d.lastUpdate = lastUpdate
+
+	if err := d.mapping.close(); err != nil {
+		d.logger.Printf("releasing previous local database mapping failed: %+v", err)
+	}
+	d.mapping = mapping
+
+	d.logger.Printf("local database loaded from %s, lists=%d, lastUpdate=%s", d.path, len(lists), lastUpdate)
+
+	return nil
+}
+
+// reload maps the snapshot file again when another process sharing the path has replaced it since it was mapped.
+// So the processes map the same file and share its pages, and the lists updated by another process are not fetched
+// again.
+func (d *Database) reload() error {
+	if d.path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(d.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.lock.RLock()
+	mapping := d.mapping
+	d.lock.RUnlock()
+
+	if mapping != nil && os.SameFile(info, mapping.info) && info.ModTime().Equal(mapping.info.ModTime()) {
+		return nil
+	}
+
+	return d.load()
+}
+
+func writeSnapshot(w io.Writer, lists []localList, lastUpdate time.Time) error {
+	sw := &snapshotWriter{w: bufio.NewWriter(w)}
+
+	sw.write([]byte(snapshotMagic))
+	sw.write(binary.BigEndian.AppendUint16(nil, snapshotFormatVersion))
+	sw.writeTime(lastUpdate)
+	sw.writeUint32(uint32(len(lists)))
+
+	for _, list := range lists {
+		metadata, err := proto2.Marshal(&proto.HashListMetadata{
+			ThreatTypes:          list.threatTypes,
+			LikelySafeTypes:      list.likelySafeTypes,
+			Description:          list.description,
+			SupportedHashLengths: list.supportedHashLengths,
+		})
+		if err != nil {
+			return err
+		}
+
+		sw.writeBytes([]byte(list.name))
+		sw.writeBytes(list.version)
+		sw.writeBytes(list.sha256Checksum)
+		sw.writeBytes(metadata)
+		sw.writeTime(list.nextUpdate)
+		sw.writeUint32(uint32(list.hashes.length))
+		sw.writeBytes(list.hashes.data)
+	}
+
+	if sw.err != nil {
+		return sw.err
+	}
+
+	return sw.w.Flush()
+}
+
+// readSnapshot parses the snapshot. The prefixes of the returned lists refer to the given data.
+func readSnapshot(data []byte) ([]localList, time.Time, error) {
+	sr := &snapshotReader{data: data}
+
+	if magic := sr.read(len(snapshotMagic)); sr.err == nil && string(magic) != snapshotMagic {
+		return nil, time.Time{}, errSnapshotFormat
+	}
+
+	if version := sr.read(2); sr.err == nil && binary.BigEndian.Uint16(version) != snapshotFormatVersion {
+		return nil, time.Time{}, fmt.Errorf("%w: version=%d", errSnapshotFormat, binary.BigEndian.Uint16(version))
+	}
+
+	lastUpdate := sr.readTime()
+	count := sr.readUint32()
+
+	var lists []localList
+
+	for i := uint32(0); i < count && sr.err == nil; i++ {
+		// Only the prefixes refer to the data, other fields are copied as they outlive it.
+		list := localList{
+			name:           string(sr.readBytes()),
+			version:        bytes.Clone(sr.readBytes()),
+			sha256Checksum: bytes.Clone(sr.readBytes()),
+		}
+
+		metadata := sr.readBytes()
+		list.nextUpdate = sr.readTime()
+		list.hashes.length = int(sr.readUint32())
+		list.hashes.data = sr.readBytes()
+
+		if sr.err != nil {
+			break
+		}
+
+		var listMetadata proto.HashListMetadata
+		if err := proto2.Unmarshal(metadata, &listMetadata); err != nil {
+			return nil, time.Time{}, fmt.Errorf("list %s metadata: %w", list.name, err)
+		}
+
+		list.description = listMetadata.Description
+		list.threatTypes = listMetadata.ThreatTypes
+		list.likelySafeTypes = listMetadata.LikelySafeTypes
+		list.supportedHashLengths = listMetadata.SupportedHashLengths
+		list.entriesCount = int32(list.hashes.len())
+
+		if list.hashes.length != 0 && len(list.hashes.data)%list.hashes.length != 0 {
+			return nil, time.Time{}, fmt.Errorf("list %s prefixes are not aligned to hash length %d", list.name, list.hashes.length)
+		}
+
+		if list.sha256Checksum != nil && !bytes.Equal(list.hashes.checksum(), list.sha256Checksum) {
+			return nil, time.Time{}, fmt.Errorf("list %s checksum mismatch", list.name)
+		}
+
+		lists = append(lists, list)
+	}
+
+	if sr.err != nil {
+		return nil, time.Time{}, sr.err
+	}
+
+	return lists, lastUpdate, nil
+}
+
+type snapshotWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (sw *snapshotWriter) write(b []byte) {
+	if sw.err == nil {
+		_, sw.err = sw.w.Write(b)
+	}
+}
+
+func (sw *snapshotWriter) writeUint32(v uint32) {
+	sw.write(binary.BigEndian.AppendUint32(nil, v))
+}
+
+func (sw *snapshotWriter) writeTime(t time.Time) {
+	var v int64
+	if !t.IsZero() {
+		v = t.UnixNano()
+	}
+
+	sw.write(binary.BigEndian.AppendUint64(nil, uint64(v)))
+}
+
+func (sw *snapshotWriter) writeBytes(b []byte) {
+	sw.writeUint32(uint32(len(b)))
+	sw.write(b)
+}
+
+type snapshotReader struct {
+	data   []byte
+	offset int
+	err    error
+}
+
+func (sr *snapshotReader) read(n int) []byte {
+	if sr.err != nil {
+		return nil
+	}
+
+	if n < 0 || len(sr.data)-sr.offset < n {
+		sr.err = io.ErrUnexpectedEOF
+		return nil
+	}
+
+	b := sr.data[sr.offset : sr.offset+n : sr.offset+n]
+	sr.offset += n
+
+	return b
+}
+
+func (sr *snapshotReader) readUint32() uint32 {
+	b := sr.read(4)
+	if b == nil {
+		return 0
+	}
+
+	return binary.BigEndian.Uint32(b)
+}
+
+func (sr *snapshotReader) readTime() time.Time {
+	b := sr.read(8)
+	if b == nil {
+		return time.Time{}
+	}
+
+	v := int64(binary.BigEndian.Uint64(b))
+	if v == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, v)
+}
+
+// readBytes returns nil for empty byte strings, so optional fields keep their zero values.
+func (sr *snapshotReader) readBytes() []byte {
+	b := sr.read(int(sr.readUint32()))
+	if len(b) == 0 {
+		return nil
+	}
+
+	return b
+}
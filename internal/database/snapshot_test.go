@@ -0,0 +1,248 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JILeXanDR/gsb-v5-tests/internal/api"
+	"github.com/JILeXanDR/gsb-v5-tests/internal/logging"
+	"github.com/JILeXanDR/gsb-v5-tests/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func newSnapshotTestLists() []localList {
+	globalCache := newHashPrefixes(32, 1)
+	hash := hashFull("example.com/")
+	globalCache = globalCache.append(hash[:])
+
+	return []localList{
+		{
+			name:                 "gc",
+			description:          "global cache",
+			hashes:               globalCache,
+			entriesCount:         1,
+			likelySafeTypes:      []proto.LikelySafeType{proto.LikelySafeType_GENERAL_BROWSING},
+			supportedHashLengths: []proto.HashLength{proto.HashLength_THIRTY_TWO_BYTES},
+			version:              []byte("gc-v1"),
+			sha256Checksum:       globalCache.checksum(),
+			nextUpdate:           time.Unix(0, time.Now().Add(time.Hour).UnixNano()),
+		},
+		{
+			name:                 "se",
+			hashes:               newUint32HashPrefixes(10, 20, 30),
+			entriesCount:         3,
+			threatTypes:          []proto.ThreatType{proto.ThreatType_SOCIAL_ENGINEERING},
+			supportedHashLengths: []proto.HashLength{proto.HashLength_FOUR_BYTES},
+			version:              []byte("se-v1"),
+		},
+	}
+}
+
+func Test_Database_saveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gsb.db")
+
+	d := newDatabase(nil, path)
+	d.lists = newSnapshotTestLists()
+	d.lastUpdate = time.Unix(0, time.Now().UnixNano())
+
+	require.NoError(t, d.save())
+
+	loaded := newDatabase(nil, path)
+	require.NoError(t, loaded.load())
+
+	assert.Equal(t, d.lists, loaded.lists)
+	assert.True(t, d.lastUpdate.Equal(loaded.lastUpdate))
+
+	require.NotNil(t, loaded.mapping)
+	for _, list := range loaded.lists {
+		assert.Contains(t, string(loaded.mapping.data), string(list.hashes.data))
+	}
+
+	require.NoError(t, loaded.Close())
+	assert.Nil(t, loaded.mapping)
+	assert.Empty(t, loaded.lists)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files must be removed")
+}
+
+func Test_readSnapshot_invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gsb.db")
+
+	d := newDatabase(nil, path)
+	d.lists = newSnapshotTestLists()
+	require.NoError(t, d.save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	t.Run("truncated", func(t *testing.T) {
+		_, _, err := readSnapshot(data[:len(data)-1])
+		require.Error(t, err)
+	})
+
+	t.Run("unknown format version", func(t *testing.T) {
+		corrupted := append([]byte(nil), data...)
+		corrupted[5] = 99
+
+		_, _, err := readSnapshot(corrupted)
+		require.ErrorIs(t, err, errSnapshotFormat)
+	})
+
+	t.Run("corrupted prefixes", func(t *testing.T) {
+		index := bytes.Index(data, d.lists[0].hashes.data)
+		require.NotEqual(t, -1, index)
+
+		corrupted := append([]byte(nil), data...)
+		corrupted[index] ^= 0xff
+
+		_, _, err := readSnapshot(corrupted)
+		require.ErrorContains(t, err, "checksum mismatch")
+	})
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gsb.db")
+
+	d := newDatabase(nil, path)
+	d.lists = newSnapshotTestLists()
+	d.lastUpdate = time.Now().Add(-10 * time.Minute)
+	require.NoError(t, d.save())
+
+	likelySafeHash := hashFull("example.com/")
+
+	t.Run("fresh database", func(t *testing.T) {
+		api := &stubAPI{}
+
+		d, err := Open(context.TODO(), api, logging.Default(), path, time.Hour)
+		require.NoError(t, err)
+		defer d.Close()
+
+		assert.Empty(t, api.batchGets, "lists must not be downloaded")
+
+		lookups := d.LookupHashes([][sha256.Size]byte{likelySafeHash})
+		assert.Equal(t, []proto.LikelySafeType{proto.LikelySafeType_GENERAL_BROWSING}, lookups[0].LikelySafeTypes)
+	})
+
+	t.Run("stale database", func(t *testing.T) {
+		api := &stubAPI{}
+
+		d, err := Open(context.TODO(), api, logging.Default(), path, time.Minute)
+		require.NoError(t, err)
+		defer d.Close()
+
+		assert.NotNil(t, d.mapping, "the updated database is mapped back")
+
+		lookups := d.LookupHashes([][sha256.Size]byte{likelySafeHash})
+		assert.Equal(t, []proto.LikelySafeType{proto.LikelySafeType_GENERAL_BROWSING}, lookups[0].LikelySafeTypes)
+
+		require.Len(t, api.batchGets, 1)
+		assert.NotContains(t, api.batchGets[0].names, "gc", "gc is not due yet")
+		assert.Contains(t, api.batchGets[0].versions, []byte("se-v1"), "the loaded versions are used for an incremental update")
+
+		loaded := newDatabase(nil, path)
+		require.NoError(t, loaded.load())
+		assert.WithinDuration(t, time.Now(), loaded.lastUpdate, time.Minute, "the database is saved after the update")
+		require.NoError(t, loaded.Close())
+	})
+
+	t.Run("missing database", func(t *testing.T) {
+		api := &stubAPI{}
+
+		d, err := Open(context.TODO(), api, logging.Default(), filepath.Join(t.TempDir(), "missing.db"), time.Hour)
+		require.NoError(t, err)
+		defer d.Close()
+
+		require.Len(t, api.batchGets, 1)
+		assert.Len(t, api.batchGets[0].names, len(recommendedLists))
+	})
+
+	t.Run("stale database, API unavailable", func(t *testing.T) {
+		stale := newDatabase(nil, path)
+		stale.lists = newSnapshotTestLists()
+		stale.lastUpdate = time.Now().Add(-10 * time.Minute)
+		require.NoError(t, stale.save())
+
+		api := &stubAPI{err: &api.Error{StatusCode: http.StatusServiceUnavailable}}
+
+		d, err := Open(context.TODO(), api, logging.Default(), path, time.Minute)
+		require.NoError(t, err, "the loaded database is used")
+		defer d.Close()
+
+		lookups := d.LookupHashes([][sha256.Size]byte{likelySafeHash})
+		assert.Equal(t, []proto.LikelySafeType{proto.LikelySafeType_GENERAL_BROWSING}, lookups[0].LikelySafeTypes)
+
+		assert.Equal(t, 1, d.backoff.Failures())
+		assert.Greater(t, d.untilNextUpdate(), 14*time.Minute, "the next update waits in the back-off mode")
+	})
+
+	t.Run("stale database, invalid API key", func(t *testing.T) {
+		api := &stubAPI{err: &api.Error{StatusCode: http.StatusBadRequest}}
+
+		_, err := Open(context.TODO(), api, logging.Default(), path, time.Minute)
+		require.Error(t, err)
+	})
+
+	t.Run("missing database, API unavailable", func(t *testing.T) {
+		api := &stubAPI{err: &api.Error{StatusCode: http.StatusServiceUnavailable}}
+
+		_, err := Open(context.TODO(), api, logging.Default(), filepath.Join(t.TempDir(), "missing.db"), time.Hour)
+		require.Error(t, err, "there is no database to use")
+	})
+}
+
+func Test_Database_sharedPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gsb.db")
+
+	// Every list waits an hour, so a list updated by one database is not due for the other.
+	newAPI := func() *stubAPI {
+		api := &stubAPI{hashLists: make(map[string][]*proto.HashList)}
+		for _, list := range recommendedLists {
+			api.hashLists[list.Name] = []*proto.HashList{
+				{Name: list.Name, Version: []byte("v1"), MinimumWaitDuration: durationpb.New(time.Hour)},
+				{Name: list.Name, Version: []byte("v2"), MinimumWaitDuration: durationpb.New(time.Hour)},
+			}
+		}
+		return api
+	}
+
+	firstAPI, secondAPI := newAPI(), newAPI()
+
+	first, err := Open(context.TODO(), firstAPI, logging.Default(), path, time.Hour)
+	require.NoError(t, err)
+	defer first.Close()
+
+	second, err := Open(context.TODO(), secondAPI, logging.Default(), path, time.Hour)
+	require.NoError(t, err)
+	defer second.Close()
+
+	require.Len(t, firstAPI.batchGets, 1)
+	assert.Empty(t, secondAPI.batchGets, "the fresh database of the first one is loaded")
+	assert.True(t, os.SameFile(first.mapping.info, second.mapping.info))
+
+	// The first database replaces the snapshot with its next update
+	for i := range first.lists {
+		first.lists[i].nextUpdate = time.Time{}
+	}
+	require.NoError(t, first.update(context.TODO()))
+	require.Len(t, firstAPI.batchGets, 2)
+	assert.False(t, os.SameFile(first.mapping.info, second.mapping.info))
+
+	// The second one maps the replaced snapshot before its own update, so its lists are no longer due
+	for i := range second.lists {
+		second.lists[i].nextUpdate = time.Time{}
+	}
+	require.NoError(t, second.update(context.TODO()))
+	assert.Empty(t, secondAPI.batchGets)
+	assert.True(t, os.SameFile(first.mapping.info, second.mapping.info))
+	assert.Equal(t, []byte("v2"), second.findList("se").version)
+}
@@ -0,0 +1,56 @@
+// Package logging is the logger the library writes its logs through. Secrets, such as the API key, are redacted
+// from every message.
+package logging
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces the secrets in the logs.
+const Redacted = "[REDACTED]"
+
+// Logger is where the library writes its logs. *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// Default is the logger which is used unless another one is set: the standard logger of the log package.
+func Default() Logger {
+	return log.Default()
+}
+
+type redactingLogger struct {
+	logger   Logger
+	replacer *strings.Replacer
+}
+
+// Redacting returns a logger which replaces the secrets in every message before it's passed to logger. The secrets are
+// also replaced in their query escaped form, in which they end up in URLs. Empty secrets are ignored.
+func Redacting(logger Logger, secrets ...string) Logger {
+	var replacements []string
+
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+
+		replacements = append(replacements, secret, Redacted)
+
+		if escaped := url.QueryEscape(secret); escaped != secret {
+			replacements = append(replacements, escaped, Redacted)
+		}
+	}
+
+	return &redactingLogger{
+		logger:   logger,
+		replacer: strings.NewReplacer(replacements...),
+	}
+}
+
+// Printf formats the message first, so secrets are redacted from the arguments too, e.g. from errors.
+func (l *redactingLogger) Printf(format string, v ...any) {
+	l.logger.Printf("%s", l.replacer.Replace(fmt.Sprintf(format, v...)))
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedacting(t *testing.T) {
+	tests := []struct {
+		name    string
+		secrets []string
+		format  string
+		args    []any
+		want    string
+	}{
+		{
+			name:    "format",
+			secrets: []string{"secret-key"},
+			format:  "send request with key secret-key",
+			want:    "send request with key [REDACTED]\n",
+		},
+		{
+			name:    "arguments",
+			secrets: []string{"secret-key"},
+			format:  "updating failed: %+v",
+			args:    []any{errors.New(`Get "https://example.com/?key=secret-key": EOF`)},
+			want:    "updating failed: Get \"https://example.com/?key=[REDACTED]\": EOF\n",
+		},
+		{
+			name:    "query escaped",
+			secrets: []string{"a/b+c"},
+			format:  "url=%s raw=%s",
+			args:    []any{"https://example.com/?key=a%2Fb%2Bc", "a/b+c"},
+			want:    "url=https://example.com/?key=[REDACTED] raw=[REDACTED]\n",
+		},
+		{
+			name:    "several secrets",
+			secrets: []string{"first", "", "second"},
+			format:  "%s and %s",
+			args:    []any{"first", "second"},
+			want:    "[REDACTED] and [REDACTED]\n",
+		},
+		{
+			name:   "no secrets",
+			format: "lists=%d %%",
+			args:   []any{6},
+			want:   "lists=6 %\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			logger := Redacting(log.New(&buf, "", 0), tt.secrets...)
+			logger.Printf(tt.format, tt.args...)
+
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package rice
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+type Golomb128BitEncoding struct {
+	FirstValueHi  uint64
+	FirstValueLo  uint64
+	RiceParameter uint32
+	EncodedData   []byte
+	EntryCount    uint32
+}
+
+// Decode decodes Rice-Golomb encoded 128-bit delta-encoded numbers.
+func (g *Golomb128BitEncoding) Decode() ([]Uint128, error) {
+	// A single entry list has no encoded data, so the rice parameter is not set.
+	if g.EntryCount > 0 && (g.RiceParameter < 64 || g.RiceParameter > 127) {
+		return nil, errors.New("invalid rice parameter: must be between 64 and 127")
+	}
+
+	firstValue := Uint128{
+		Hi: g.FirstValueHi,
+		Lo: g.FirstValueLo,
+	}
+
+	decodedValues := make([]Uint128, g.EntryCount+1)
+	decodedValues[0] = firstValue
+
+	bitStream := NewBitStream256(g.EncodedData)
+	currentValue := firstValue
+
+	for i := uint32(0); i < g.EntryCount; i++ {
+		quotient, err := bitStream.ReadUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		// The remainder is written starting from the least significant bit, so the lower 64 bits come first.
+		lo, err := bitStream.ReadBits(64)
+		if err != nil {
+			return nil, err
+		}
+		hi, err := bitStream.ReadBits(g.RiceParameter - 64)
+		if err != nil {
+			return nil, err
+		}
+
+		delta := Uint128{
+			Hi: (quotient << (g.RiceParameter - 64)) | hi,
+			Lo: lo,
+		}
+
+		currentValue = currentValue.Add(delta)
+		decodedValues[i+1] = currentValue
+	}
+
+	return decodedValues, nil
+}
+
+// Uint128 represents a 128-bit unsigned integer using two 64-bit parts.
+type Uint128 struct {
+	Hi uint64 // First 64 bits
+	Lo uint64 // Last 64 bits
+}
+
+// Add adds a 128-bit delta to the current Uint128 value.
+func (u Uint128) Add(delta Uint128) Uint128 {
+	lo, carry := bits.Add64(u.Lo, delta.Lo, 0)
+	hi, _ := bits.Add64(u.Hi, delta.Hi, carry)
+
+	return Uint128{Hi: hi, Lo: lo}
+}
+
+// AppendBytes appends the big-endian representation of the value to b.
+func (u Uint128) AppendBytes(b []byte) []byte {
+	b = binary.BigEndian.AppendUint64(b, u.Hi)
+	b = binary.BigEndian.AppendUint64(b, u.Lo)
+	return b
+}
@@ -0,0 +1,69 @@
+package rice
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeUint128HashPrefixes(t *testing.T) {
+	values := sortedHashPrefixes(16, "a.example.com/", "b.example.com/", "y.example.com/", "z.example.com/")
+
+	first := values[0].FillBytes(make([]byte, 16))
+
+	enc := &Golomb128BitEncoding{
+		FirstValueHi:  new(big.Int).SetBytes(first[:8]).Uint64(),
+		FirstValueLo:  new(big.Int).SetBytes(first[8:]).Uint64(),
+		RiceParameter: 124,
+		EncodedData:   riceEncode(values, 124),
+		EntryCount:    uint32(len(values) - 1),
+	}
+
+	decodedPrefixes, err := enc.Decode()
+	require.NoError(t, err)
+	require.Len(t, decodedPrefixes, len(values))
+
+	for i, value := range values {
+		assert.Equal(t, value.FillBytes(make([]byte, 16)), decodedPrefixes[i].AppendBytes(nil))
+	}
+
+	// The stream is written out by hand, so it doesn't depend on riceEncode. Bits are packed starting from the least
+	// significant bit of each byte, a delta is the unary quotient followed by the 100 bits of the remainder:
+	//
+	//	delta 1:           bit 0 = 0 (quotient 0), bit 1 = 1 (remainder 1), bits 2-100 = 0
+	//	delta 2^100+2^64:  bit 101 = 1, bit 102 = 0 (quotient 1), bit 167 = 1 (remainder 2^64), other bits = 0
+	t.Run("known answer", func(t *testing.T) {
+		data := make([]byte, 26)
+		data[0] = 0x02  // bit 1
+		data[12] = 0x20 // bit 101
+		data[20] = 0x80 // bit 167
+
+		enc := &Golomb128BitEncoding{
+			FirstValueHi:  0,
+			FirstValueLo:  ^uint64(0),
+			RiceParameter: 100,
+			EncodedData:   data,
+			EntryCount:    2,
+		}
+
+		decodedPrefixes, err := enc.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, []Uint128{
+			{Hi: 0, Lo: ^uint64(0)},
+			{Hi: 1, Lo: 0},             // the carry into the high part
+			{Hi: 1<<36 + 1 + 1, Lo: 0}, // 2^100 is bit 36 of the high part
+		}, decodedPrefixes)
+	})
+
+	t.Run("single entry", func(t *testing.T) {
+		decodedPrefixes, err := (&Golomb128BitEncoding{FirstValueHi: 1, FirstValueLo: 2}).Decode()
+		require.NoError(t, err)
+		assert.Equal(t, []Uint128{{Hi: 1, Lo: 2}}, decodedPrefixes)
+	})
+}
+
+func TestUint128_Add(t *testing.T) {
+	assert.Equal(t, Uint128{Hi: 2, Lo: 0}, Uint128{Hi: 1, Lo: ^uint64(0)}.Add(Uint128{Lo: 1}))
+}
@@ -1,10 +1,12 @@
-package main
+package rice
 
 import (
+	"encoding/binary"
 	"errors"
+	"math/bits"
 )
 
-type golomb256BitEncoding struct {
+type Golomb256BitEncoding struct {
 	FirstValuePart1 uint64
 	FirstValuePart2 uint64
 	FirstValuePart3 uint64
@@ -15,8 +17,13 @@ type golomb256BitEncoding struct {
 }
 
 // Decode decodes Rice-Golomb encoded 256-bit delta-encoded numbers.
-func (g *golomb256BitEncoding) Decode() ([]Uint256, error) {
-	if g.RiceParameter < 227 || g.RiceParameter > 254 {
+//
+// Each delta is a unary-encoded quotient followed by a remainder of RiceParameter bits, written starting from the
+// least significant bit. The remainder is longer than 192 bits, so it spans all four parts and only its highest
+// bits share the first part with the quotient.
+func (g *Golomb256BitEncoding) Decode() ([]Uint256, error) {
+	// A single entry list has no encoded data, so the rice parameter is not set.
+	if g.EntryCount > 0 && (g.RiceParameter < 227 || g.RiceParameter > 254) {
 		return nil, errors.New("invalid rice parameter: must be between 227 and 254")
 	}
 
@@ -33,8 +40,8 @@ func (g *golomb256BitEncoding) Decode() ([]Uint256, error) {
 	bitStream := NewBitStream256(g.EncodedData)
 	currentValue := firstValue
 
-	// Determine remainder size for each part
-	remainderBits := g.RiceParameter / 4
+	// Bits of the remainder which belong to the first part
+	highBits := g.RiceParameter - 192
 
 	for i := uint32(0); i < g.EntryCount; i++ {
 		// Read the unary-encoded quotient
@@ -43,27 +50,27 @@ func (g *golomb256BitEncoding) Decode() ([]Uint256, error) {
 			return nil, err
 		}
 
-		// Read the remainder parts
-		r1, err := bitStream.ReadBits(remainderBits)
+		// Read the remainder parts, the least significant one first
+		r4, err := bitStream.ReadBits(64)
 		if err != nil {
 			return nil, err
 		}
-		r2, err := bitStream.ReadBits(remainderBits)
+		r3, err := bitStream.ReadBits(64)
 		if err != nil {
 			return nil, err
 		}
-		r3, err := bitStream.ReadBits(remainderBits)
+		r2, err := bitStream.ReadBits(64)
 		if err != nil {
 			return nil, err
 		}
-		r4, err := bitStream.ReadBits(remainderBits)
+		r1, err := bitStream.ReadBits(highBits)
 		if err != nil {
 			return nil, err
 		}
 
 		// Combine quotient and remainders into a delta
 		delta := Uint256{
-			Part1: (quotient << remainderBits) | r1,
+			Part1: (quotient << highBits) | r1,
 			Part2: r2,
 			Part3: r3,
 			Part4: r4,
@@ -136,27 +143,26 @@ type Uint256 struct {
 	Part4 uint64 // Last 64 bits
 }
 
-// Add adds a 256-bit delta to the current Uint256 value.
+// Add adds a 256-bit delta to the current Uint256 value. The carry is propagated from the last part to the first one,
+// an overflow of the first part is discarded.
 func (u Uint256) Add(delta Uint256) Uint256 {
-	p4 := u.Part4 + delta.Part4
-	p3 := u.Part3 + delta.Part3
-	p2 := u.Part2 + delta.Part2
-	p1 := u.Part1 + delta.Part1
-
-	// Handle carry propagation
-	if p4 < u.Part4 {
-		p3++
-	}
-	if p3 < u.Part3 {
-		p2++
-	}
-	if p2 < u.Part2 {
-		p1++
-	}
+	p4, carry := bits.Add64(u.Part4, delta.Part4, 0)
+	p3, carry := bits.Add64(u.Part3, delta.Part3, carry)
+	p2, carry := bits.Add64(u.Part2, delta.Part2, carry)
+	p1, _ := bits.Add64(u.Part1, delta.Part1, carry)
 
 	return Uint256{Part1: p1, Part2: p2, Part3: p3, Part4: p4}
 }
 
+// AppendBytes appends the big-endian representation of the value to b.
+func (u Uint256) AppendBytes(b []byte) []byte {
+	b = binary.BigEndian.AppendUint64(b, u.Part1)
+	b = binary.BigEndian.AppendUint64(b, u.Part2)
+	b = binary.BigEndian.AppendUint64(b, u.Part3)
+	b = binary.BigEndian.AppendUint64(b, u.Part4)
+	return b
+}
+
 // Compare compares two Uint256 values. Returns:
 //   - -1 if u < other
 //   - 0 if u == other
@@ -0,0 +1,163 @@
+package rice
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUint256FromBig(value *big.Int) Uint256 {
+	var u Uint256
+
+	u.Part4 = new(big.Int).And(value, new(big.Int).SetUint64(^uint64(0))).Uint64()
+	u.Part3 = new(big.Int).And(new(big.Int).Rsh(value, 64), new(big.Int).SetUint64(^uint64(0))).Uint64()
+	u.Part2 = new(big.Int).And(new(big.Int).Rsh(value, 128), new(big.Int).SetUint64(^uint64(0))).Uint64()
+	u.Part1 = new(big.Int).Rsh(value, 192).Uint64()
+
+	return u
+}
+
+func newGolomb256BitEncoding(values []*big.Int, riceParameter uint32) *Golomb256BitEncoding {
+	first := newUint256FromBig(values[0])
+
+	return &Golomb256BitEncoding{
+		FirstValuePart1: first.Part1,
+		FirstValuePart2: first.Part2,
+		FirstValuePart3: first.Part3,
+		FirstValuePart4: first.Part4,
+		RiceParameter:   riceParameter,
+		EncodedData:     riceEncode(values, uint(riceParameter)),
+		EntryCount:      uint32(len(values) - 1),
+	}
+}
+
+// The full hashes of the expressions are published in the docs,
+// see https://developers.google.com/safe-browsing/reference#decoding-hashes-and-hash-prefixes. They are encoded by
+// riceEncode, so it's a round trip, the decoder is checked against independent streams by the known-answer test.
+func TestDecodeUint256Hashes_roundTrip(t *testing.T) {
+	values := sortedHashPrefixes(32, "a.example.com/", "b.example.com/", "y.example.com/")
+
+	for _, riceParameter := range []uint32{245, 250, 254} {
+		enc := newGolomb256BitEncoding(values, riceParameter)
+
+		decodedHashes, err := enc.Decode()
+		require.NoError(t, err)
+		require.Len(t, decodedHashes, 3)
+
+		assert.Equal(t, "1d32c5084a360e58f1b87109637a6810acad97a861a7769e8f1841410d2a960c", hexUint256(decodedHashes[0]), "rice=%d", riceParameter)
+		assert.Equal(t, "291bc5421f1cd54d99afcc55d166e2b9fe42447025895bf09dd41b2110a687dc", hexUint256(decodedHashes[1]), "rice=%d", riceParameter)
+		assert.Equal(t, "f7a502e56e8b01c6dc242b35122683c9d25d07fb1f532d9853eb0ef3ff334f03", hexUint256(decodedHashes[2]), "rice=%d", riceParameter)
+	}
+}
+
+func TestDecodeUint256Hashes_carries(t *testing.T) {
+	maxPart := new(big.Int).SetUint64(^uint64(0))
+
+	values := []*big.Int{
+		new(big.Int).Set(maxPart),                                             // 0x00..00ffffffffffffffff
+		new(big.Int).Lsh(big.NewInt(1), 64),                                   // carry into the third part
+		new(big.Int).Lsh(big.NewInt(1), 192),                                  // carry into the first part
+		new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)), // all bits set
+	}
+
+	enc := newGolomb256BitEncoding(values, 254)
+
+	decodedHashes, err := enc.Decode()
+	require.NoError(t, err)
+	require.Len(t, decodedHashes, len(values))
+
+	for i, value := range values {
+		assert.Equal(t, newUint256FromBig(value), decodedHashes[i])
+	}
+}
+
+// The stream is written out by hand, so it doesn't depend on riceEncode. Bits are packed starting from the least
+// significant bit of each byte, a delta is the unary quotient followed by the 230 bits of the remainder:
+//
+//	delta 1:           bit 0 = 0 (quotient 0), bit 1 = 1 (remainder 1), bits 2-230 = 0
+//	delta 2^230+2^192: bit 231 = 1, bit 232 = 0 (quotient 1), bit 425 = 1 (remainder 2^192), other bits = 0
+func TestDecodeUint256Hashes_knownAnswer(t *testing.T) {
+	data := make([]byte, 58)
+	data[0] = 0x02  // bit 1
+	data[28] = 0x80 // bit 231
+	data[53] = 0x02 // bit 425
+
+	enc := &Golomb256BitEncoding{
+		FirstValuePart3: ^uint64(0),
+		FirstValuePart4: ^uint64(0),
+		RiceParameter:   230,
+		EncodedData:     data,
+		EntryCount:      2,
+	}
+
+	decodedHashes, err := enc.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, []Uint256{
+		{Part3: ^uint64(0), Part4: ^uint64(0)},
+		{Part2: 1},                   // the carry across two parts
+		{Part1: 1<<38 + 1, Part2: 1}, // 2^230 is bit 38 of the first part
+	}, decodedHashes)
+}
+
+func TestDecodeUint256Hashes_validation(t *testing.T) {
+	t.Run("single entry", func(t *testing.T) {
+		decodedHashes, err := (&Golomb256BitEncoding{FirstValuePart1: 1, FirstValuePart4: 4}).Decode()
+		require.NoError(t, err)
+		assert.Equal(t, []Uint256{{Part1: 1, Part4: 4}}, decodedHashes)
+	})
+
+	t.Run("invalid rice parameter", func(t *testing.T) {
+		_, err := (&Golomb256BitEncoding{RiceParameter: 226, EntryCount: 1}).Decode()
+		require.Error(t, err)
+	})
+
+	t.Run("truncated data", func(t *testing.T) {
+		values := sortedHashPrefixes(32, "a.example.com/", "b.example.com/")
+
+		enc := newGolomb256BitEncoding(values, 250)
+		enc.EncodedData = enc.EncodedData[:len(enc.EncodedData)-1]
+
+		_, err := enc.Decode()
+		require.Error(t, err)
+	})
+}
+
+func TestUint256_Add(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    Uint256
+		delta    Uint256
+		expected Uint256
+	}{
+		{
+			name:     "no carry",
+			value:    Uint256{Part1: 1, Part2: 2, Part3: 3, Part4: 4},
+			delta:    Uint256{Part1: 1, Part2: 1, Part3: 1, Part4: 1},
+			expected: Uint256{Part1: 2, Part2: 3, Part3: 4, Part4: 5},
+		},
+		{
+			name:     "carry through all parts",
+			value:    Uint256{Part2: ^uint64(0), Part3: ^uint64(0), Part4: ^uint64(0)},
+			delta:    Uint256{Part4: 1},
+			expected: Uint256{Part1: 1},
+		},
+		{
+			name:     "carry into a part which overflows with the carry only",
+			value:    Uint256{Part3: 5, Part4: ^uint64(0)},
+			delta:    Uint256{Part3: ^uint64(0), Part4: 1},
+			expected: Uint256{Part2: 1, Part3: 5},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.value.Add(test.delta))
+		})
+	}
+}
+
+func hexUint256(u Uint256) string {
+	return new(big.Int).SetBytes(u.AppendBytes(nil)).Text(16)
+}
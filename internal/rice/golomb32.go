@@ -1,20 +1,21 @@
-package main
+// Package rice decodes the Rice-Golomb encoded hash prefixes and indices of the Safe Browsing lists.
+package rice
 
 import "errors"
 
-type golomb32BitEncoding struct {
+type Golomb32BitEncoding struct {
 	FirstValue    uint32
 	RiceParameter uint32
 	EncodedData   []byte
 	EntryCount    uint32
 }
 
-// func (g *golomb32BitEncoding) Encode(hashes []uint32) error {
+// func (g *Golomb32BitEncoding) Encode(hashes []uint32) error {
 // 	panic("not implemented")
 // 	return nil
 // }
 
-func (g *golomb32BitEncoding) Decode() ([]uint32, error) {
+func (g *Golomb32BitEncoding) Decode() ([]uint32, error) {
 	if g.RiceParameter > 31 {
 		return nil, errors.New("invalid rice parameter: must be <= 31")
 	}
@@ -1,16 +1,17 @@
-package main
+package rice
 
 import (
 	"crypto/sha256"
 	"encoding/binary"
 	"fmt"
 	"log"
+	"math/big"
 	"slices"
 	"testing"
 
+	"github.com/JILeXanDR/gsb-v5-tests/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"gsb-v5-tests/proto"
 )
 
 // https://developers.google.com/safe-browsing/reference#decoding-hashes-and-hash-prefixes
@@ -23,10 +24,10 @@ func TestDecodeUint32HashPrefixes_fromExample(t *testing.T) {
 		FirstValue:    489866504,
 		RiceParameter: 30,
 		EntriesCount:  2,
-		EncodedData:   []byte(`t\000\322\227\033\355It\000`),
+		EncodedData:   []byte("t\000\322\227\033\355It\000"),
 	}
 
-	enc := &golomb32BitEncoding{
+	enc := &Golomb32BitEncoding{
 		FirstValue:    additionsFourBytes.FirstValue,
 		RiceParameter: uint32(additionsFourBytes.RiceParameter),
 		EncodedData:   additionsFourBytes.EncodedData,
@@ -38,8 +39,8 @@ func TestDecodeUint32HashPrefixes_fromExample(t *testing.T) {
 	require.Equal(t, 3, len(decodedPrefixes))
 
 	// hash=489866504
-	// hash=894104386
-	// hash=1736331122
+	// hash=689685826
+	// hash=4154786533
 	for _, hash := range decodedPrefixes {
 		log.Printf("hash=%v", hash)
 	}
@@ -59,7 +60,7 @@ func TestDecodeUint32HashPrefixes_fromExample(t *testing.T) {
 		hash := sha256.Sum256([]byte("a.example.com/"))
 		assert.Equal(t, "291bc5421f1cd54d99afcc55d166e2b9fe42447025895bf09dd41b2110a687dc", fmt.Sprintf("%x", hash))
 		assert.EqualValues(t, 0x291bc542, binary.BigEndian.Uint32(hash[:4]))
-		// assert.EqualValues(t, 689685826, binary.BigEndian.Uint32(hash[:4])) TODO: doesn't with result hash
+		assert.EqualValues(t, 689685826, binary.BigEndian.Uint32(hash[:4]))
 
 		index, found := slices.BinarySearch(decodedPrefixes, hashUint32FourBytes("a.example.com/"))
 		require.Equal(t, true, found)
@@ -70,10 +71,80 @@ func TestDecodeUint32HashPrefixes_fromExample(t *testing.T) {
 		hash := sha256.Sum256([]byte("y.example.com/"))
 		assert.Equal(t, "f7a502e56e8b01c6dc242b35122683c9d25d07fb1f532d9853eb0ef3ff334f03", fmt.Sprintf("%x", hash))
 		assert.EqualValues(t, 0xf7a502e5, binary.BigEndian.Uint32(hash[:4]))
-		// assert.EqualValues(t, 4154786533, binary.BigEndian.Uint32(hash[:4])) TODO: doesn't with result hash
+		assert.EqualValues(t, uint32(4154786533), binary.BigEndian.Uint32(hash[:4]))
 
 		index, found := slices.BinarySearch(decodedPrefixes, hashUint32FourBytes("y.example.com/"))
 		require.Equal(t, true, found)
 		assert.Equal(t, 2, index)
 	})
 }
+
+// riceEncode encodes the sorted values as the first value and Rice-Golomb encoded deltas, the way the server does it.
+func riceEncode(values []*big.Int, riceParameter uint) []byte {
+	var (
+		data   []byte
+		bitPos int
+	)
+
+	writeBit := func(bit uint) {
+		if bitPos%8 == 0 {
+			data = append(data, 0)
+		}
+		data[bitPos/8] |= byte(bit) << (bitPos % 8)
+		bitPos++
+	}
+
+	for i := 1; i < len(values); i++ {
+		delta := new(big.Int).Sub(values[i], values[i-1])
+
+		quotient := new(big.Int).Rsh(delta, riceParameter).Uint64()
+		for j := uint64(0); j < quotient; j++ {
+			writeBit(1)
+		}
+		writeBit(0)
+
+		for j := 0; j < int(riceParameter); j++ {
+			writeBit(delta.Bit(j))
+		}
+	}
+
+	return data
+}
+
+// sortedHashPrefixes returns the sorted prefixes of the given length of the expressions full hashes.
+func sortedHashPrefixes(length int, expressions ...string) []*big.Int {
+	values := make([]*big.Int, len(expressions))
+
+	for i, expression := range expressions {
+		hash := sha256.Sum256([]byte(expression))
+		values[i] = new(big.Int).SetBytes(hash[:length])
+	}
+
+	slices.SortFunc(values, (*big.Int).Cmp)
+
+	return values
+}
+
+func hashUint32FourBytes(input string) uint32 {
+	hash := sha256.Sum256([]byte(input))
+	return binary.BigEndian.Uint32(hash[:4])
+}
+
+func TestDecodeUint32HashPrefixes_roundTrip(t *testing.T) {
+	values := sortedHashPrefixes(4, "a.example.com/", "b.example.com/", "y.example.com/")
+
+	enc := &Golomb32BitEncoding{
+		FirstValue:    uint32(values[0].Uint64()),
+		RiceParameter: 28,
+		EncodedData:   riceEncode(values, 28),
+		EntryCount:    uint32(len(values) - 1),
+	}
+
+	decodedPrefixes, err := enc.Decode()
+	require.NoError(t, err)
+	require.Len(t, decodedPrefixes, len(values))
+
+	for i, value := range values {
+		assert.EqualValues(t, value.Uint64(), decodedPrefixes[i])
+	}
+}
@@ -0,0 +1,41 @@
+package rice
+
+import "errors"
+
+type Golomb64BitEncoding struct {
+	FirstValue    uint64
+	RiceParameter uint32
+	EncodedData   []byte
+	EntryCount    uint32
+}
+
+// Decode decodes Rice-Golomb encoded 64-bit delta-encoded numbers.
+func (g *Golomb64BitEncoding) Decode() ([]uint64, error) {
+	if g.RiceParameter > 63 {
+		return nil, errors.New("invalid rice parameter: must be <= 63")
+	}
+
+	decodedValues := make([]uint64, g.EntryCount+1)
+	decodedValues[0] = g.FirstValue
+
+	bitStream := NewBitStream256(g.EncodedData)
+	currentValue := g.FirstValue
+
+	for i := uint32(0); i < g.EntryCount; i++ {
+		quotient, err := bitStream.ReadUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		remainder, err := bitStream.ReadBits(g.RiceParameter)
+		if err != nil {
+			return nil, err
+		}
+
+		adjacentDifference := (quotient << g.RiceParameter) | remainder
+		currentValue += adjacentDifference
+		decodedValues[i+1] = currentValue
+	}
+
+	return decodedValues, nil
+}
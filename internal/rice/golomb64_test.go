@@ -0,0 +1,47 @@
+package rice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeUint64HashPrefixes(t *testing.T) {
+	values := sortedHashPrefixes(8, "a.example.com/", "b.example.com/", "y.example.com/", "z.example.com/")
+
+	enc := &Golomb64BitEncoding{
+		FirstValue:    values[0].Uint64(),
+		RiceParameter: 61,
+		EncodedData:   riceEncode(values, 61),
+		EntryCount:    uint32(len(values) - 1),
+	}
+
+	decodedPrefixes, err := enc.Decode()
+	require.NoError(t, err)
+	require.Len(t, decodedPrefixes, len(values))
+
+	for i, value := range values {
+		assert.Equal(t, value.Uint64(), decodedPrefixes[i])
+	}
+
+	// The stream of the published 4-byte example, see TestDecodeUint32HashPrefixes_fromExample. The Rice coding is
+	// the same for all lengths, so it's a known answer for the 64-bit decoder too.
+	t.Run("published example", func(t *testing.T) {
+		enc := &Golomb64BitEncoding{
+			FirstValue:    489866504,
+			RiceParameter: 30,
+			EncodedData:   []byte("t\000\322\227\033\355It\000"),
+			EntryCount:    2,
+		}
+
+		decodedPrefixes, err := enc.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{489866504, 689685826, 4154786533}, decodedPrefixes)
+	})
+
+	t.Run("invalid rice parameter", func(t *testing.T) {
+		_, err := (&Golomb64BitEncoding{RiceParameter: 64}).Decode()
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,374 @@
+package urls
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// CanonicalURL is a URL canonicalized as described in
+// https://developers.google.com/safe-browsing/reference/URLs.Hashing#canonicalization.
+// All parts are percent-escaped, the port, user info and fragment are removed.
+type CanonicalURL struct {
+	scheme   string
+	host     string
+	path     string
+	query    string
+	hasQuery bool
+}
+
+func (u *CanonicalURL) String() string {
+	var b strings.Builder
+
+	b.WriteString(u.scheme)
+	b.WriteString("://")
+	b.WriteString(u.host)
+	b.WriteString(u.path)
+
+	if u.hasQuery {
+		b.WriteByte('?')
+		b.WriteString(u.query)
+	}
+
+	return b.String()
+}
+
+// Parse splits the URL into parts and canonicalizes each of them. The URL is parsed by hand instead of
+// net/url, because the URLs to check are often not valid: they may have control characters, invalid escapes and
+// escaped hostnames, and they may have no scheme.
+func Parse(rawURL string) (*CanonicalURL, error) {
+	// Remove tab (0x09), CR (0x0d) and LF (0x0a) characters, and leading and trailing whitespaces
+	rawURL = strings.NewReplacer("\t", "", "\r", "", "\n", "").Replace(rawURL)
+	rawURL = strings.TrimSpace(rawURL)
+
+	// Remove the fragment, it's never sent to a server
+	rawURL, _, _ = strings.Cut(rawURL, "#")
+
+	u := &CanonicalURL{scheme: "http"}
+
+	rest := rawURL
+	if scheme, afterScheme, ok := strings.Cut(rawURL, "://"); ok && isScheme(scheme) {
+		u.scheme = strings.ToLower(scheme)
+		rest = afterScheme
+	}
+
+	authority := rest
+	rest = ""
+	if i := strings.IndexAny(authority, "/?"); i != -1 {
+		authority, rest = authority[:i], authority[i:]
+	}
+
+	rawPath, rawQuery, hasQuery := strings.Cut(rest, "?")
+
+	host := removePort(removeUserInfo(authority))
+
+	u.host = escape(canonicalizeHostname(unescape(host)))
+	if u.host == "" {
+		return nil, ErrEmptyHost
+	}
+
+	u.path = escape(canonicalizePath(unescape(rawPath)))
+	u.query = escape(unescape(rawQuery))
+	u.hasQuery = hasQuery
+
+	return u, nil
+}
+
+// ErrEmptyHost is reported for URLs without a host, nothing can be looked up for them.
+var ErrEmptyHost = errors.New("empty host")
+
+func isScheme(scheme string) bool {
+	if scheme == "" {
+		return false
+	}
+
+	for i, c := range scheme {
+		switch {
+		case 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+		case i > 0 && ('0' <= c && c <= '9' || c == '+' || c == '-' || c == '.'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func removeUserInfo(authority string) string {
+	if i := strings.LastIndex(authority, "@"); i != -1 {
+		return authority[i+1:]
+	}
+
+	return authority
+}
+
+func removePort(hostport string) string {
+	// The colons of IPv6 literals are inside brackets
+	if strings.HasPrefix(hostport, "[") {
+		if i := strings.Index(hostport, "]"); i != -1 {
+			return hostport[:i+1]
+		}
+	}
+
+	if i := strings.LastIndex(hostport, ":"); i != -1 {
+		return hostport[:i]
+	}
+
+	return hostport
+}
+
+// canonicalizeHostname converts internationalized hostnames to punycode, removes leading and trailing dots, replaces
+// consecutive dots with a single one, normalizes IP addresses and lowercases the hostname. The hostname must be
+// unescaped.
+func canonicalizeHostname(hostname string) string {
+	hostname = toASCIIHostname(hostname)
+	hostname = strings.Trim(hostname, ".")
+
+	for strings.Contains(hostname, "..") {
+		hostname = strings.ReplaceAll(hostname, "..", ".")
+	}
+
+	if ip := parseIPv4(hostname); ip != nil {
+		return ip.String()
+	}
+
+	if ip := parseIPv6(hostname); ip != nil {
+		// IPv4-mapped addresses refer to the same host as the IPv4 address
+		if ip4 := ip.To4(); ip4 != nil {
+			return ip4.String()
+		}
+
+		return "[" + ip.String() + "]"
+	}
+
+	return toLowerASCII(hostname)
+}
+
+// idnaProfile maps hostnames the way browsers do before a lookup, so a homograph domain and its "xn--" form are
+// hashed the same way.
+var idnaProfile = idna.New(idna.MapForLookup(), idna.Transitional(false))
+
+// toASCIIHostname converts the hostname to its ASCII form with IDNA (UTS-46). Hostnames which can't be converted,
+// e.g. because of invalid UTF-8 or disallowed characters, are returned as is and escaped later.
+func toASCIIHostname(hostname string) string {
+	if isASCII(hostname) {
+		return hostname
+	}
+
+	ascii, err := idnaProfile.ToASCII(hostname)
+	if err != nil {
+		return hostname
+	}
+
+	return ascii
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseIPv6 parses the hostname as a bracketed IPv6 literal. It returns nil if the hostname is not one.
+func parseIPv6(hostname string) net.IP {
+	if !strings.HasPrefix(hostname, "[") || !strings.HasSuffix(hostname, "]") {
+		return nil
+	}
+
+	literal := hostname[1 : len(hostname)-1]
+	if !strings.Contains(literal, ":") {
+		return nil
+	}
+
+	return net.ParseIP(literal)
+}
+
+// isIPHost reports whether the canonical hostname is an IP address.
+func isIPHost(hostname string) bool {
+	return parseIPv4(hostname) != nil || parseIPv6(hostname) != nil
+}
+
+// parseIPv4 parses the hostname as an IPv4 address in any form accepted by inet_aton: one to four components, each
+// of them decimal, octal with a leading "0" or hexadecimal with a leading "0x". The last component fills all the
+// remaining bytes of the address, so "1.2.3" is 1.2.0.3 and "3232235777" is 192.168.1.1. It returns nil if the
+// hostname is not an IPv4 address.
+func parseIPv4(hostname string) net.IP {
+	parts := strings.Split(hostname, ".")
+	if len(parts) > net.IPv4len {
+		return nil
+	}
+
+	var ip uint32
+
+	for i, part := range parts {
+		n, ok := parseIPv4Component(part)
+		if !ok {
+			return nil
+		}
+
+		// All components but the last one are single bytes
+		bits := 8
+		if i == len(parts)-1 {
+			bits = 8 * (net.IPv4len - i)
+		}
+
+		if n >= 1<<bits {
+			return nil
+		}
+
+		ip = ip<<bits | uint32(n)
+	}
+
+	return net.IPv4(byte(ip>>24), byte(ip>>16), byte(ip>>8), byte(ip)).To4()
+}
+
+func parseIPv4Component(s string) (uint64, bool) {
+	base := 10
+
+	switch {
+	case strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X"):
+		// inet_aton accepts a bare "0x" as zero
+		if s = s[2:]; s == "" {
+			return 0, true
+		}
+		base = 16
+	case len(s) > 1 && s[0] == '0':
+		s = s[1:]
+		base = 8
+	}
+
+	// ParseUint accepts signs and underscores in some bases, none of them are valid here
+	for i := 0; i < len(s); i++ {
+		if !isHex(s[i]) {
+			return 0, false
+		}
+	}
+
+	n, err := strconv.ParseUint(s, base, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// canonicalizePath resolves "." and ".." segments and replaces consecutive slashes with a single one.
+// The path must be unescaped.
+func canonicalizePath(path string) string {
+	var segments []string
+
+	parts := strings.Split(path, "/")
+	for _, part := range parts {
+		switch part {
+		case "", ".":
+		case "..":
+			if len(segments) > 0 {
+				segments = segments[:len(segments)-1]
+			}
+		default:
+			segments = append(segments, part)
+		}
+	}
+
+	if len(segments) == 0 {
+		return "/"
+	}
+
+	canonical := "/" + strings.Join(segments, "/")
+
+	switch last := parts[len(parts)-1]; last {
+	case "", ".", "..":
+		canonical += "/"
+	}
+
+	return canonical
+}
+
+// unescape repeatedly percent-unescapes the string until it has no more percent-escapes. Invalid escapes are kept
+// as is.
+func unescape(s string) string {
+	for {
+		unescaped := unescapeOnce(s)
+		if unescaped == s {
+			return s
+		}
+		s = unescaped
+	}
+}
+
+func unescapeOnce(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	b := make([]byte, 0, len(s))
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			b = append(b, unhex(s[i+1])<<4|unhex(s[i+2]))
+			i += 2
+			continue
+		}
+
+		b = append(b, s[i])
+	}
+
+	return string(b)
+}
+
+// escape percent-escapes the characters <= ASCII 32, >= ASCII 127, "#" and "%". The escapes use uppercase hex.
+func escape(s string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= 0x20 || c >= 0x7f || c == '#' || c == '%' {
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&15])
+			continue
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+// toLowerASCII lowercases only ASCII letters, so bytes of escaped non-ASCII characters are kept intact.
+func toLowerASCII(s string) string {
+	b := []byte(s)
+
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+
+	return string(b)
+}
@@ -0,0 +1,116 @@
+// Package urls canonicalizes URLs and generates the host suffix and path prefix expressions which are looked up.
+package urls
+
+import (
+	"slices"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// Options tune how the expressions of a URL are generated.
+type Options struct {
+	// ETLDPlusOneHostSuffixes stops host suffixes at the registrable domain, so e.g. "co.uk" is never looked up.
+	ETLDPlusOneHostSuffixes bool
+}
+
+// Expressions returns the host suffix and path prefix combinations of the canonical URL which are looked up.
+func (u *CanonicalURL) Expressions(opts Options) []string {
+	// Generate host suffixes
+	hostSuffixes := generateHostSuffixes(u.host, opts)
+
+	// Generate path prefixes
+	pathPrefixes := generatePathPrefixes(u.path, u.query)
+
+	return combinePrefixesAndSuffixes(hostSuffixes, pathPrefixes)
+}
+
+func combinePrefixesAndSuffixes(hosts []string, paths []string) []string {
+	var combinations []string
+	for _, host := range hosts {
+		for _, path := range paths {
+			combinations = append(combinations, host+path)
+		}
+	}
+	return combinations
+}
+
+// maxHostSuffixComponents is the number of the last hostname components the suffixes are formed from.
+const maxHostSuffixComponents = 5
+
+// generateHostSuffixes returns the exact hostname and up to four hostnames formed by starting with the last five
+// components and successively removing the leading component. The top-level domain is skipped.
+func generateHostSuffixes(hostname string, opts Options) []string {
+	suffixes := []string{hostname}
+
+	// IP addresses have no suffixes, only the exact address is looked up
+	if isIPHost(hostname) {
+		return suffixes
+	}
+
+	parts := strings.Split(hostname, ".")
+
+	// The shortest suffix has two components, or as many as the registrable domain if requested
+	minComponents := 2
+	if opts.ETLDPlusOneHostSuffixes {
+		// Unlisted TLDs fall back to the plain rule
+		if baseDomain, err := publicsuffix.EffectiveTLDPlusOne(hostname); err == nil {
+			minComponents = strings.Count(baseDomain, ".") + 1
+		}
+	}
+
+	for i := max(1, len(parts)-maxHostSuffixComponents); i <= len(parts)-minComponents; i++ {
+		suffixes = append(suffixes, strings.Join(parts[i:], "."))
+	}
+
+	return suffixes
+}
+
+// maxPathPrefixComponents is the number of leading path components the path prefixes are formed from.
+const maxPathPrefixComponents = 3
+
+// generatePathPrefixes returns at most six paths: the exact path with the query, the exact path without the query and
+// the four paths formed by starting at the root and successively appending path components with a trailing slash.
+// The path must be canonical, the paths are returned without duplicates in this order.
+func generatePathPrefixes(path string, query string) []string {
+	var prefixes []string
+
+	add := func(prefix string) {
+		if !slices.Contains(prefixes, prefix) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	// Add the full path with query string if present
+	if query != "" {
+		add(path + "?" + query)
+	}
+
+	// Add the full path without query string
+	add(path)
+
+	// Add the root path and the intermediate path prefixes. The last component is a file name or empty for paths
+	// ending with a slash, it's only a part of the full path.
+	components := strings.Split(path, "/")
+	components = components[:len(components)-1]
+
+	prefix := "/"
+	add(prefix)
+
+	appended := 0
+	for _, component := range components {
+		if appended == maxPathPrefixComponents {
+			break
+		}
+
+		if component == "" {
+			continue
+		}
+
+		prefix += component + "/"
+		add(prefix)
+		appended++
+	}
+
+	return prefixes
+}
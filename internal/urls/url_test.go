@@ -0,0 +1,492 @@
+package urls
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generateExpressions canonicalizes the URL and returns its expressions.
+func generateExpressions(rawURL string, opts Options) ([]string, error) {
+	canonical, err := Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return canonical.Expressions(opts), nil
+}
+
+func Test_generateHostSuffixes(t *testing.T) {
+	tests := []struct {
+		input    string
+		options  Options
+		expected []string
+	}{
+		{
+			input: "a.b.com",
+			expected: []string{
+				"a.b.com",
+				"b.com",
+			},
+		},
+		{
+			input: "a.b.c.d.e.f.com",
+			expected: []string{
+				"a.b.c.d.e.f.com",
+				"c.d.e.f.com",
+				"d.e.f.com",
+				"e.f.com",
+				"f.com",
+			},
+		},
+		{
+			input: "1.2.3.4",
+			expected: []string{
+				"1.2.3.4",
+			},
+		},
+		{
+			input: "192.168.1.1",
+			expected: []string{
+				"192.168.1.1",
+			},
+		},
+		{
+			input: "10.0.0.255",
+			expected: []string{
+				"10.0.0.255",
+			},
+		},
+		{
+			input: "[2001:db8::1]",
+			expected: []string{
+				"[2001:db8::1]",
+			},
+		},
+		{
+			input: "example.co.uk",
+			expected: []string{
+				"example.co.uk",
+				"co.uk",
+			},
+		},
+		{
+			input:   "example.co.uk",
+			options: Options{ETLDPlusOneHostSuffixes: true},
+			expected: []string{
+				"example.co.uk",
+			},
+		},
+		{
+			input:   "a.b.example.co.uk",
+			options: Options{ETLDPlusOneHostSuffixes: true},
+			expected: []string{
+				"a.b.example.co.uk",
+				"b.example.co.uk",
+				"example.co.uk",
+			},
+		},
+		{
+			input:   "co.uk",
+			options: Options{ETLDPlusOneHostSuffixes: true},
+			expected: []string{
+				"co.uk",
+			},
+		},
+		{
+			input: "a.b.c.d.e.f.g",
+			expected: []string{
+				"a.b.c.d.e.f.g",
+				"c.d.e.f.g",
+				"d.e.f.g",
+				"e.f.g",
+				"f.g",
+			},
+		},
+		{
+			input: "a.b.unlisted-tld",
+			expected: []string{
+				"a.b.unlisted-tld",
+				"b.unlisted-tld",
+			},
+		},
+		{
+			input: "localhost",
+			expected: []string{
+				"localhost",
+			},
+		},
+		{
+			input: "a.example.com",
+			expected: []string{
+				"a.example.com",
+				"example.com",
+			},
+		},
+		{
+			input: "a.b.c.d.e.f.example.com",
+			expected: []string{
+				"example.com",
+				"f.example.com",
+				"e.f.example.com",
+				"d.e.f.example.com",
+				"a.b.c.d.e.f.example.com",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			suffixes := generateHostSuffixes(test.input, test.options)
+
+			slices.Sort(test.expected)
+			slices.Sort(suffixes)
+
+			assert.Equal(t, test.expected, suffixes)
+		})
+	}
+}
+
+func TestGetAllSuffixPrefixCombinations(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		options  Options
+		expected []string
+	}{
+		{
+			input: "http://a.b.com/1/2.html?param=1",
+			expected: []string{
+				"a.b.com/1/2.html?param=1",
+				"a.b.com/1/2.html",
+				"a.b.com/",
+				"a.b.com/1/",
+				"b.com/1/2.html?param=1",
+				"b.com/1/2.html",
+				"b.com/",
+				"b.com/1/",
+			},
+		},
+		{
+			input: "http://a.b.c.d.e.f.com/1.html",
+			expected: []string{
+				"a.b.c.d.e.f.com/1.html",
+				"a.b.c.d.e.f.com/",
+				"c.d.e.f.com/1.html",
+				"c.d.e.f.com/",
+				"d.e.f.com/1.html",
+				"d.e.f.com/",
+				"e.f.com/1.html",
+				"e.f.com/",
+				"f.com/1.html",
+				"f.com/",
+			},
+		},
+		{
+			input: "http://1.2.3.4/1/",
+			expected: []string{
+				"1.2.3.4/1/",
+				"1.2.3.4/",
+			},
+		},
+		{
+			input: "http://0x7f.0.0.1/1/",
+			expected: []string{
+				"127.0.0.1/1/",
+				"127.0.0.1/",
+			},
+		},
+		{
+			input: "http://3232235777/",
+			expected: []string{
+				"192.168.1.1/",
+			},
+		},
+		{
+			input: "http://[2001:db8::1]/x",
+			expected: []string{
+				"[2001:db8::1]/x",
+				"[2001:db8::1]/",
+			},
+		},
+		{
+			input: "http://[::ffff:1.2.3.4]:8080/1/",
+			expected: []string{
+				"1.2.3.4/1/",
+				"1.2.3.4/",
+			},
+		},
+		{
+			input: "http://www.пример.рф/1",
+			expected: []string{
+				"www.xn--e1afmkfd.xn--p1ai/1",
+				"www.xn--e1afmkfd.xn--p1ai/",
+				"xn--e1afmkfd.xn--p1ai/1",
+				"xn--e1afmkfd.xn--p1ai/",
+			},
+		},
+		{
+			input: "http://www.xn--e1afmkfd.xn--p1ai/1",
+			expected: []string{
+				"www.xn--e1afmkfd.xn--p1ai/1",
+				"www.xn--e1afmkfd.xn--p1ai/",
+				"xn--e1afmkfd.xn--p1ai/1",
+				"xn--e1afmkfd.xn--p1ai/",
+			},
+		},
+		{
+			input: "http://example.co.uk/1",
+			expected: []string{
+				"example.co.uk/1",
+				"example.co.uk/",
+				"co.uk/1",
+				"co.uk/",
+			},
+		},
+		{
+			input:   "http://example.co.uk/1",
+			options: Options{ETLDPlusOneHostSuffixes: true},
+			expected: []string{
+				"example.co.uk/1",
+				"example.co.uk/",
+			},
+		},
+		{
+			input:   "http://example.co.uk/1/2/3",
+			options: Options{ETLDPlusOneHostSuffixes: true},
+			expected: []string{
+				"example.co.uk/",
+				"example.co.uk/1/",
+				"example.co.uk/1/2/",
+				"example.co.uk/1/2/3",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			suffixes, err := generateExpressions(test.input, test.options)
+			require.NoError(t, err)
+
+			slices.Sort(test.expected)
+			slices.Sort(suffixes)
+
+			assert.Equal(t, test.expected, suffixes)
+		})
+	}
+}
+
+func Test_generatePathPrefixes(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		query    string
+		expected []string
+	}{
+		{
+			name:  "only slash",
+			path:  "/",
+			query: "",
+			expected: []string{
+				"/",
+			},
+		},
+		{
+			name:  "slash plus query",
+			path:  "/",
+			query: "test=1",
+			expected: []string{
+				"/?test=1",
+				"/",
+			},
+		},
+		{
+			name:  "slash with path",
+			path:  "/path",
+			query: "",
+			expected: []string{
+				"/path",
+				"/",
+			},
+		},
+		{
+			name:  "slash with path plus query",
+			path:  "/path",
+			query: "query=1",
+			expected: []string{
+				"/path?query=1",
+				"/path",
+				"/",
+			},
+		},
+		{
+			name:  "path with 5 parts plus query",
+			path:  "/path/1/2/3/4",
+			query: "query=1",
+			expected: []string{
+				"/path/1/2/3/4?query=1",
+				"/path/1/2/3/4",
+				"/",
+				"/path/",
+				"/path/1/",
+				"/path/1/2/",
+			},
+		},
+		{
+			name: "trailing slash",
+			path: "/1/2/",
+			expected: []string{
+				"/1/2/",
+				"/",
+				"/1/",
+			},
+		},
+		{
+			name:  "trailing slash plus query",
+			path:  "/1/2/",
+			query: "q",
+			expected: []string{
+				"/1/2/?q",
+				"/1/2/",
+				"/",
+				"/1/",
+			},
+		},
+		{
+			name: "trailing slash with 4 parts",
+			path: "/1/2/3/4/",
+			expected: []string{
+				"/1/2/3/4/",
+				"/",
+				"/1/",
+				"/1/2/",
+				"/1/2/3/",
+			},
+		},
+		{
+			name: "trailing slash with 3 parts",
+			path: "/1/2/3/",
+			expected: []string{
+				"/1/2/3/",
+				"/",
+				"/1/",
+				"/1/2/",
+			},
+		},
+		{
+			name: "empty segments",
+			path: "//1///2.html",
+			expected: []string{
+				"//1///2.html",
+				"/",
+				"/1/",
+			},
+		},
+		{
+			name: "file in root",
+			path: "/1.html",
+			expected: []string{
+				"/1.html",
+				"/",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			prefixes := generatePathPrefixes(test.path, test.query)
+
+			assert.Equal(t, test.expected, prefixes)
+		})
+	}
+}
+
+// The cases are the examples published in
+// https://developers.google.com/safe-browsing/reference/URLs.Hashing#suffixprefix-expressions.
+func TestGenerateExpressions_conformance(t *testing.T) {
+	tests := []struct {
+		input    string
+		options  Options
+		expected []string
+	}{
+		{
+			input: "http://a.b.c/1/2.html?param=1",
+			expected: []string{
+				"a.b.c/1/2.html?param=1",
+				"a.b.c/1/2.html",
+				"a.b.c/",
+				"a.b.c/1/",
+				"b.c/1/2.html?param=1",
+				"b.c/1/2.html",
+				"b.c/",
+				"b.c/1/",
+			},
+		},
+		{
+			input: "http://a.b.c.d.e.f.g/1.html",
+			expected: []string{
+				"a.b.c.d.e.f.g/1.html",
+				"a.b.c.d.e.f.g/",
+				"c.d.e.f.g/1.html",
+				"c.d.e.f.g/",
+				"d.e.f.g/1.html",
+				"d.e.f.g/",
+				"e.f.g/1.html",
+				"e.f.g/",
+				"f.g/1.html",
+				"f.g/",
+			},
+		},
+		{
+			input: "http://1.2.3.4/1/",
+			expected: []string{
+				"1.2.3.4/1/",
+				"1.2.3.4/",
+			},
+		},
+		{
+			input:   "http://example.co.uk/1",
+			options: Options{ETLDPlusOneHostSuffixes: true},
+			expected: []string{
+				"example.co.uk/1",
+				"example.co.uk/",
+			},
+		},
+		{
+			input: "http://a.b.com?param=1",
+			expected: []string{
+				"a.b.com/?param=1",
+				"a.b.com/",
+				"b.com/?param=1",
+				"b.com/",
+			},
+		},
+		{
+			input: "http://a.b.com/1/2/3/4/5/6.html",
+			expected: []string{
+				"a.b.com/1/2/3/4/5/6.html",
+				"a.b.com/",
+				"a.b.com/1/",
+				"a.b.com/1/2/",
+				"a.b.com/1/2/3/",
+				"b.com/1/2/3/4/5/6.html",
+				"b.com/",
+				"b.com/1/",
+				"b.com/1/2/",
+				"b.com/1/2/3/",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			expressions, err := generateExpressions(test.input, test.options)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, expressions)
+		})
+	}
+}
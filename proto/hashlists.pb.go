@@ -76,6 +76,55 @@ func (ThreatType) EnumDescriptor() ([]byte, []int) {
 	return file_proto_hashlists_proto_rawDescGZIP(), []int{0}
 }
 
+type ThreatAttribute int32
+
+const (
+	ThreatAttribute_THREAT_ATTRIBUTE_UNSPECIFIED ThreatAttribute = 0
+	ThreatAttribute_CANARY                       ThreatAttribute = 1 // Indicates that the threatType should not be used for enforcement.
+	ThreatAttribute_FRAME_ONLY                   ThreatAttribute = 2 // Indicates that the threatType should only be used for enforcement on frames.
+)
+
+// Enum value maps for ThreatAttribute.
+var (
+	ThreatAttribute_name = map[int32]string{
+		0: "THREAT_ATTRIBUTE_UNSPECIFIED",
+		1: "CANARY",
+		2: "FRAME_ONLY",
+	}
+	ThreatAttribute_value = map[string]int32{
+		"THREAT_ATTRIBUTE_UNSPECIFIED": 0,
+		"CANARY":                       1,
+		"FRAME_ONLY":                   2,
+	}
+)
+
+func (x ThreatAttribute) Enum() *ThreatAttribute {
+	p := new(ThreatAttribute)
+	*p = x
+	return p
+}
+
+func (x ThreatAttribute) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ThreatAttribute) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_hashlists_proto_enumTypes[1].Descriptor()
+}
+
+func (ThreatAttribute) Type() protoreflect.EnumType {
+	return &file_proto_hashlists_proto_enumTypes[1]
+}
+
+func (x ThreatAttribute) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ThreatAttribute.Descriptor instead.
+func (ThreatAttribute) EnumDescriptor() ([]byte, []int) {
+	return file_proto_hashlists_proto_rawDescGZIP(), []int{1}
+}
+
 type LikelySafeType int32
 
 const (
@@ -112,11 +161,11 @@ func (x LikelySafeType) String() string {
 }
 
 func (LikelySafeType) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_hashlists_proto_enumTypes[1].Descriptor()
+	return file_proto_hashlists_proto_enumTypes[2].Descriptor()
 }
 
 func (LikelySafeType) Type() protoreflect.EnumType {
-	return &file_proto_hashlists_proto_enumTypes[1]
+	return &file_proto_hashlists_proto_enumTypes[2]
 }
 
 func (x LikelySafeType) Number() protoreflect.EnumNumber {
@@ -125,7 +174,7 @@ func (x LikelySafeType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use LikelySafeType.Descriptor instead.
 func (LikelySafeType) EnumDescriptor() ([]byte, []int) {
-	return file_proto_hashlists_proto_rawDescGZIP(), []int{1}
+	return file_proto_hashlists_proto_rawDescGZIP(), []int{2}
 }
 
 type HashLength int32
@@ -167,11 +216,11 @@ func (x HashLength) String() string {
 }
 
 func (HashLength) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_hashlists_proto_enumTypes[2].Descriptor()
+	return file_proto_hashlists_proto_enumTypes[3].Descriptor()
 }
 
 func (HashLength) Type() protoreflect.EnumType {
-	return &file_proto_hashlists_proto_enumTypes[2]
+	return &file_proto_hashlists_proto_enumTypes[3]
 }
 
 func (x HashLength) Number() protoreflect.EnumNumber {
@@ -180,7 +229,7 @@ func (x HashLength) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use HashLength.Descriptor instead.
 func (HashLength) EnumDescriptor() ([]byte, []int) {
-	return file_proto_hashlists_proto_rawDescGZIP(), []int{2}
+	return file_proto_hashlists_proto_rawDescGZIP(), []int{3}
 }
 
 type ListHashListsResponse struct {
@@ -235,19 +284,22 @@ type HashList struct {
 
 	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`       // The name of the hash list. Note that the Global Cache is also just a hash list and can be referred to here.
 	Version []byte `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"` // The version of the hash list. The client MUST NOT manipulate those bytes. A base64-encoded string.
+	// When true, this is a partial diff containing additions and removals based on what the client already has. When false, this is the complete hash list.
+	PartialUpdate bool `protobuf:"varint,3,opt,name=partialUpdate,proto3" json:"partialUpdate,omitempty"`
 	// The Rice-delta encoded version of removal indices. Since each hash list definitely has less than 2^32 entries, the indices are treated as 32-bit integers and encoded.
-	CompressedRemovals *RiceDeltaEncoded32Bit `protobuf:"bytes,4,opt,name=compressedRemovals,proto3" json:"compressedRemovals,omitempty"`
+	CompressedRemovals *RiceDeltaEncoded32Bit `protobuf:"bytes,5,opt,name=compressedRemovals,proto3" json:"compressedRemovals,omitempty"`
 	// Clients should wait at least this long to get the hash list again.
 	// If omitted or zero, clients SHOULD fetch immediately because it indicates that the server has an additional update to be sent to the client, but could not due to the client-specified constraints.
 	MinimumWaitDuration *durationpb.Duration `protobuf:"bytes,6,opt,name=minimumWaitDuration,proto3" json:"minimumWaitDuration,omitempty"`
 	// Types that are assignable to Checksum:
-	//
 	//	*HashList_Sha256Checksum
 	Checksum isHashList_Checksum `protobuf_oneof:"checksum"`
 	// Metadata about the hash list. This is not populated by the hashList.get method, but this is populated by the ListHashLists method.
 	Metadata *HashListMetadata `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
 	// Types that are assignable to CompressedAdditions:
-	//
+	//	*HashList_AdditionsFourBytes
+	//	*HashList_AdditionsEightBytes
+	//	*HashList_AdditionsSixteenBytes
 	//	*HashList_AdditionsThirtyTwoBytes
 	CompressedAdditions isHashList_CompressedAdditions `protobuf_oneof:"compressed_additions"`
 }
@@ -296,6 +348,13 @@ func (x *HashList) GetVersion() []byte {
 	return nil
 }
 
+func (x *HashList) GetPartialUpdate() bool {
+	if x != nil {
+		return x.PartialUpdate
+	}
+	return false
+}
+
 func (x *HashList) GetCompressedRemovals() *RiceDeltaEncoded32Bit {
 	if x != nil {
 		return x.CompressedRemovals
@@ -338,6 +397,27 @@ func (m *HashList) GetCompressedAdditions() isHashList_CompressedAdditions {
 	return nil
 }
 
+func (x *HashList) GetAdditionsFourBytes() *RiceDeltaEncoded32Bit {
+	if x, ok := x.GetCompressedAdditions().(*HashList_AdditionsFourBytes); ok {
+		return x.AdditionsFourBytes
+	}
+	return nil
+}
+
+func (x *HashList) GetAdditionsEightBytes() *RiceDeltaEncoded64Bit {
+	if x, ok := x.GetCompressedAdditions().(*HashList_AdditionsEightBytes); ok {
+		return x.AdditionsEightBytes
+	}
+	return nil
+}
+
+func (x *HashList) GetAdditionsSixteenBytes() *RiceDeltaEncoded128Bit {
+	if x, ok := x.GetCompressedAdditions().(*HashList_AdditionsSixteenBytes); ok {
+		return x.AdditionsSixteenBytes
+	}
+	return nil
+}
+
 func (x *HashList) GetAdditionsThirtyTwoBytes() *RiceDeltaEncoded256Bit {
 	if x, ok := x.GetCompressedAdditions().(*HashList_AdditionsThirtyTwoBytes); ok {
 		return x.AdditionsThirtyTwoBytes
@@ -350,7 +430,7 @@ type isHashList_Checksum interface {
 }
 
 type HashList_Sha256Checksum struct {
-	Sha256Checksum []byte `protobuf:"bytes,7,opt,name=sha256Checksum,proto3,oneof"` // Base64-encoded string
+	Sha256Checksum []byte `protobuf:"bytes,7,opt,name=sha256Checksum,proto3,oneof"`
 }
 
 func (*HashList_Sha256Checksum) isHashList_Checksum() {}
@@ -359,10 +439,28 @@ type isHashList_CompressedAdditions interface {
 	isHashList_CompressedAdditions()
 }
 
+type HashList_AdditionsFourBytes struct {
+	AdditionsFourBytes *RiceDeltaEncoded32Bit `protobuf:"bytes,4,opt,name=additionsFourBytes,proto3,oneof"`
+}
+
+type HashList_AdditionsEightBytes struct {
+	AdditionsEightBytes *RiceDeltaEncoded64Bit `protobuf:"bytes,9,opt,name=additionsEightBytes,proto3,oneof"`
+}
+
+type HashList_AdditionsSixteenBytes struct {
+	AdditionsSixteenBytes *RiceDeltaEncoded128Bit `protobuf:"bytes,10,opt,name=additionsSixteenBytes,proto3,oneof"`
+}
+
 type HashList_AdditionsThirtyTwoBytes struct {
 	AdditionsThirtyTwoBytes *RiceDeltaEncoded256Bit `protobuf:"bytes,11,opt,name=additionsThirtyTwoBytes,proto3,oneof"`
 }
 
+func (*HashList_AdditionsFourBytes) isHashList_CompressedAdditions() {}
+
+func (*HashList_AdditionsEightBytes) isHashList_CompressedAdditions() {}
+
+func (*HashList_AdditionsSixteenBytes) isHashList_CompressedAdditions() {}
+
 func (*HashList_AdditionsThirtyTwoBytes) isHashList_CompressedAdditions() {}
 
 type RiceDeltaEncoded32Bit struct {
@@ -373,7 +471,7 @@ type RiceDeltaEncoded32Bit struct {
 	FirstValue    uint32 `protobuf:"varint,1,opt,name=firstValue,proto3" json:"firstValue,omitempty"`
 	RiceParameter int32  `protobuf:"varint,2,opt,name=riceParameter,proto3" json:"riceParameter,omitempty"`
 	EntriesCount  int32  `protobuf:"varint,3,opt,name=entriesCount,proto3" json:"entriesCount,omitempty"`
-	EncodedData   []byte `protobuf:"bytes,4,opt,name=encodedData,proto3" json:"encodedData,omitempty"` // Base64-encoded string
+	EncodedData   []byte `protobuf:"bytes,4,opt,name=encodedData,proto3" json:"encodedData,omitempty"`
 }
 
 func (x *RiceDeltaEncoded32Bit) Reset() {
@@ -434,6 +532,152 @@ func (x *RiceDeltaEncoded32Bit) GetEncodedData() []byte {
 	return nil
 }
 
+type RiceDeltaEncoded64Bit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FirstValue    uint64 `protobuf:"varint,1,opt,name=firstValue,proto3" json:"firstValue,omitempty"`
+	RiceParameter int32  `protobuf:"varint,2,opt,name=riceParameter,proto3" json:"riceParameter,omitempty"`
+	EntriesCount  int32  `protobuf:"varint,3,opt,name=entriesCount,proto3" json:"entriesCount,omitempty"`
+	EncodedData   []byte `protobuf:"bytes,4,opt,name=encodedData,proto3" json:"encodedData,omitempty"`
+}
+
+func (x *RiceDeltaEncoded64Bit) Reset() {
+	*x = RiceDeltaEncoded64Bit{}
+	mi := &file_proto_hashlists_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RiceDeltaEncoded64Bit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RiceDeltaEncoded64Bit) ProtoMessage() {}
+
+func (x *RiceDeltaEncoded64Bit) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hashlists_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RiceDeltaEncoded64Bit.ProtoReflect.Descriptor instead.
+func (*RiceDeltaEncoded64Bit) Descriptor() ([]byte, []int) {
+	return file_proto_hashlists_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RiceDeltaEncoded64Bit) GetFirstValue() uint64 {
+	if x != nil {
+		return x.FirstValue
+	}
+	return 0
+}
+
+func (x *RiceDeltaEncoded64Bit) GetRiceParameter() int32 {
+	if x != nil {
+		return x.RiceParameter
+	}
+	return 0
+}
+
+func (x *RiceDeltaEncoded64Bit) GetEntriesCount() int32 {
+	if x != nil {
+		return x.EntriesCount
+	}
+	return 0
+}
+
+func (x *RiceDeltaEncoded64Bit) GetEncodedData() []byte {
+	if x != nil {
+		return x.EncodedData
+	}
+	return nil
+}
+
+type RiceDeltaEncoded128Bit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FirstValueHi  uint64 `protobuf:"varint,1,opt,name=firstValueHi,proto3" json:"firstValueHi,omitempty"`  // The upper 64 bits of the first entry.
+	FirstValueLo  uint64 `protobuf:"fixed64,2,opt,name=firstValueLo,proto3" json:"firstValueLo,omitempty"` // The lower 64 bits of the first entry.
+	RiceParameter int32  `protobuf:"varint,3,opt,name=riceParameter,proto3" json:"riceParameter,omitempty"`
+	EntriesCount  int32  `protobuf:"varint,4,opt,name=entriesCount,proto3" json:"entriesCount,omitempty"`
+	EncodedData   []byte `protobuf:"bytes,5,opt,name=encodedData,proto3" json:"encodedData,omitempty"`
+}
+
+func (x *RiceDeltaEncoded128Bit) Reset() {
+	*x = RiceDeltaEncoded128Bit{}
+	mi := &file_proto_hashlists_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RiceDeltaEncoded128Bit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RiceDeltaEncoded128Bit) ProtoMessage() {}
+
+func (x *RiceDeltaEncoded128Bit) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hashlists_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RiceDeltaEncoded128Bit.ProtoReflect.Descriptor instead.
+func (*RiceDeltaEncoded128Bit) Descriptor() ([]byte, []int) {
+	return file_proto_hashlists_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RiceDeltaEncoded128Bit) GetFirstValueHi() uint64 {
+	if x != nil {
+		return x.FirstValueHi
+	}
+	return 0
+}
+
+func (x *RiceDeltaEncoded128Bit) GetFirstValueLo() uint64 {
+	if x != nil {
+		return x.FirstValueLo
+	}
+	return 0
+}
+
+func (x *RiceDeltaEncoded128Bit) GetRiceParameter() int32 {
+	if x != nil {
+		return x.RiceParameter
+	}
+	return 0
+}
+
+func (x *RiceDeltaEncoded128Bit) GetEntriesCount() int32 {
+	if x != nil {
+		return x.EntriesCount
+	}
+	return 0
+}
+
+func (x *RiceDeltaEncoded128Bit) GetEncodedData() []byte {
+	if x != nil {
+		return x.EncodedData
+	}
+	return nil
+}
+
 type RiceDeltaEncoded256Bit struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -445,12 +689,12 @@ type RiceDeltaEncoded256Bit struct {
 	FirstValueFourthPart uint64 `protobuf:"fixed64,4,opt,name=firstValueFourthPart,proto3" json:"firstValueFourthPart,omitempty"`
 	RiceParameter        int32  `protobuf:"varint,5,opt,name=riceParameter,proto3" json:"riceParameter,omitempty"`
 	EntriesCount         int32  `protobuf:"varint,6,opt,name=entriesCount,proto3" json:"entriesCount,omitempty"`
-	EncodedData          []byte `protobuf:"bytes,7,opt,name=encodedData,proto3" json:"encodedData,omitempty"` // Base64-encoded string
+	EncodedData          []byte `protobuf:"bytes,7,opt,name=encodedData,proto3" json:"encodedData,omitempty"`
 }
 
 func (x *RiceDeltaEncoded256Bit) Reset() {
 	*x = RiceDeltaEncoded256Bit{}
-	mi := &file_proto_hashlists_proto_msgTypes[3]
+	mi := &file_proto_hashlists_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -462,7 +706,7 @@ func (x *RiceDeltaEncoded256Bit) String() string {
 func (*RiceDeltaEncoded256Bit) ProtoMessage() {}
 
 func (x *RiceDeltaEncoded256Bit) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_hashlists_proto_msgTypes[3]
+	mi := &file_proto_hashlists_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -475,7 +719,7 @@ func (x *RiceDeltaEncoded256Bit) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RiceDeltaEncoded256Bit.ProtoReflect.Descriptor instead.
 func (*RiceDeltaEncoded256Bit) Descriptor() ([]byte, []int) {
-	return file_proto_hashlists_proto_rawDescGZIP(), []int{3}
+	return file_proto_hashlists_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *RiceDeltaEncoded256Bit) GetFirstValueFirstPart() uint64 {
@@ -527,6 +771,170 @@ func (x *RiceDeltaEncoded256Bit) GetEncodedData() []byte {
 	return nil
 }
 
+type SearchHashesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Unordered list. The unordered list of full hashes found.
+	FullHashes []*FullHash `protobuf:"bytes,1,rep,name=fullHashes,proto3" json:"fullHashes,omitempty"`
+	// The client-side cache duration. The client MUST add this duration to the current time to determine the expiration time.
+	CacheDuration *durationpb.Duration `protobuf:"bytes,2,opt,name=cacheDuration,proto3" json:"cacheDuration,omitempty"`
+}
+
+func (x *SearchHashesResponse) Reset() {
+	*x = SearchHashesResponse{}
+	mi := &file_proto_hashlists_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchHashesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchHashesResponse) ProtoMessage() {}
+
+func (x *SearchHashesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hashlists_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchHashesResponse.ProtoReflect.Descriptor instead.
+func (*SearchHashesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_hashlists_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SearchHashesResponse) GetFullHashes() []*FullHash {
+	if x != nil {
+		return x.FullHashes
+	}
+	return nil
+}
+
+func (x *SearchHashesResponse) GetCacheDuration() *durationpb.Duration {
+	if x != nil {
+		return x.CacheDuration
+	}
+	return nil
+}
+
+type FullHash struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The matching full hash. This is the SHA256 hash. The length will be exactly 32 bytes.
+	FullHash []byte `protobuf:"bytes,1,opt,name=fullHash,proto3" json:"fullHash,omitempty"`
+	// Unordered list. A repeated field identifying the details relevant to this full hash.
+	FullHashDetails []*FullHashDetail `protobuf:"bytes,2,rep,name=fullHashDetails,proto3" json:"fullHashDetails,omitempty"`
+}
+
+func (x *FullHash) Reset() {
+	*x = FullHash{}
+	mi := &file_proto_hashlists_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FullHash) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FullHash) ProtoMessage() {}
+
+func (x *FullHash) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hashlists_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FullHash.ProtoReflect.Descriptor instead.
+func (*FullHash) Descriptor() ([]byte, []int) {
+	return file_proto_hashlists_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FullHash) GetFullHash() []byte {
+	if x != nil {
+		return x.FullHash
+	}
+	return nil
+}
+
+func (x *FullHash) GetFullHashDetails() []*FullHashDetail {
+	if x != nil {
+		return x.FullHashDetails
+	}
+	return nil
+}
+
+type FullHashDetail struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ThreatType ThreatType `protobuf:"varint,1,opt,name=threatType,proto3,enum=proto.ThreatType" json:"threatType,omitempty"`
+	// Unordered list. Additional attributes about those full hashes. This may be empty.
+	Attributes []ThreatAttribute `protobuf:"varint,2,rep,packed,name=attributes,proto3,enum=proto.ThreatAttribute" json:"attributes,omitempty"`
+}
+
+func (x *FullHashDetail) Reset() {
+	*x = FullHashDetail{}
+	mi := &file_proto_hashlists_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FullHashDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FullHashDetail) ProtoMessage() {}
+
+func (x *FullHashDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hashlists_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FullHashDetail.ProtoReflect.Descriptor instead.
+func (*FullHashDetail) Descriptor() ([]byte, []int) {
+	return file_proto_hashlists_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *FullHashDetail) GetThreatType() ThreatType {
+	if x != nil {
+		return x.ThreatType
+	}
+	return ThreatType_THREAT_TYPE_UNSPECIFIED
+}
+
+func (x *FullHashDetail) GetAttributes() []ThreatAttribute {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
 type HashListMetadata struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -541,7 +949,7 @@ type HashListMetadata struct {
 
 func (x *HashListMetadata) Reset() {
 	*x = HashListMetadata{}
-	mi := &file_proto_hashlists_proto_msgTypes[4]
+	mi := &file_proto_hashlists_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -553,7 +961,7 @@ func (x *HashListMetadata) String() string {
 func (*HashListMetadata) ProtoMessage() {}
 
 func (x *HashListMetadata) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_hashlists_proto_msgTypes[4]
+	mi := &file_proto_hashlists_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -566,7 +974,7 @@ func (x *HashListMetadata) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HashListMetadata.ProtoReflect.Descriptor instead.
 func (*HashListMetadata) Descriptor() ([]byte, []int) {
-	return file_proto_hashlists_proto_rawDescGZIP(), []int{4}
+	return file_proto_hashlists_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *HashListMetadata) GetThreatTypes() []ThreatType {
@@ -615,107 +1023,176 @@ var file_proto_hashlists_proto_rawDesc = []byte{
 	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x09, 0x68, 0x61, 0x73, 0x68, 0x4c,
 	0x69, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x09, 0x68, 0x61, 0x73,
-	0x68, 0x4c, 0x69, 0x73, 0x74, 0x73, 0x22, 0xb1, 0x03, 0x0a, 0x08, 0x48, 0x61, 0x73, 0x68, 0x4c,
+	0x68, 0x4c, 0x69, 0x73, 0x74, 0x73, 0x22, 0xd0, 0x05, 0x0a, 0x08, 0x48, 0x61, 0x73, 0x68, 0x4c,
 	0x69, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
 	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
-	0x6e, 0x12, 0x4c, 0x0a, 0x12, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x52,
-	0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
+	0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x70, 0x61, 0x72, 0x74, 0x69, 0x61,
+	0x6c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x4c, 0x0a, 0x12, 0x63, 0x6f, 0x6d, 0x70, 0x72,
+	0x65, 0x73, 0x73, 0x65, 0x64, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x69, 0x63, 0x65,
+	0x44, 0x65, 0x6c, 0x74, 0x61, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x33, 0x32, 0x42, 0x69,
+	0x74, 0x52, 0x12, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x52, 0x65, 0x6d,
+	0x6f, 0x76, 0x61, 0x6c, 0x73, 0x12, 0x4b, 0x0a, 0x13, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d,
+	0x57, 0x61, 0x69, 0x74, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x13, 0x6d,
+	0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x57, 0x61, 0x69, 0x74, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x0e, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x73, 0x75, 0x6d, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x0e, 0x73, 0x68,
+	0x61, 0x32, 0x35, 0x36, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x12, 0x33, 0x0a, 0x08,
+	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x4c, 0x69, 0x73, 0x74, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x4e, 0x0a, 0x12, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x46, 0x6f,
+	0x75, 0x72, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x69, 0x63, 0x65, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x45,
-	0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x33, 0x32, 0x42, 0x69, 0x74, 0x52, 0x12, 0x63, 0x6f, 0x6d,
-	0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x73, 0x12,
-	0x4b, 0x0a, 0x13, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x57, 0x61, 0x69, 0x74, 0x44, 0x75,
-	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44,
-	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x13, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d,
-	0x57, 0x61, 0x69, 0x74, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x0e,
-	0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x0e, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x43, 0x68,
-	0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x12, 0x33, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
-	0x74, 0x61, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x48, 0x61, 0x73, 0x68, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
-	0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x59, 0x0a, 0x17, 0x61,
-	0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x54, 0x68, 0x69, 0x72, 0x74, 0x79, 0x54, 0x77,
-	0x6f, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x69, 0x63, 0x65, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x45, 0x6e,
-	0x63, 0x6f, 0x64, 0x65, 0x64, 0x32, 0x35, 0x36, 0x42, 0x69, 0x74, 0x48, 0x01, 0x52, 0x17, 0x61,
-	0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x54, 0x68, 0x69, 0x72, 0x74, 0x79, 0x54, 0x77,
-	0x6f, 0x42, 0x79, 0x74, 0x65, 0x73, 0x42, 0x0a, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73,
-	0x75, 0x6d, 0x42, 0x16, 0x0a, 0x14, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64,
-	0x5f, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xa3, 0x01, 0x0a, 0x15, 0x52,
-	0x69, 0x63, 0x65, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x33,
-	0x32, 0x42, 0x69, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c,
-	0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56,
-	0x61, 0x6c, 0x75, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x69, 0x63, 0x65, 0x50, 0x61, 0x72, 0x61,
-	0x6d, 0x65, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x72, 0x69, 0x63,
-	0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x6e,
-	0x74, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x0c, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x20,
-	0x0a, 0x0b, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x0b, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61,
-	0x22, 0xd0, 0x02, 0x0a, 0x16, 0x52, 0x69, 0x63, 0x65, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x45, 0x6e,
-	0x63, 0x6f, 0x64, 0x65, 0x64, 0x32, 0x35, 0x36, 0x42, 0x69, 0x74, 0x12, 0x30, 0x0a, 0x13, 0x66,
-	0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x46, 0x69, 0x72, 0x73, 0x74, 0x50, 0x61,
-	0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x13, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56,
-	0x61, 0x6c, 0x75, 0x65, 0x46, 0x69, 0x72, 0x73, 0x74, 0x50, 0x61, 0x72, 0x74, 0x12, 0x32, 0x0a,
+	0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x33, 0x32, 0x42, 0x69, 0x74, 0x48, 0x01, 0x52, 0x12, 0x61,
+	0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x46, 0x6f, 0x75, 0x72, 0x42, 0x79, 0x74, 0x65,
+	0x73, 0x12, 0x50, 0x0a, 0x13, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x69,
+	0x67, 0x68, 0x74, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x69, 0x63, 0x65, 0x44, 0x65, 0x6c, 0x74, 0x61,
+	0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x36, 0x34, 0x42, 0x69, 0x74, 0x48, 0x01, 0x52, 0x13,
+	0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x69, 0x67, 0x68, 0x74, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x12, 0x55, 0x0a, 0x15, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x53, 0x69, 0x78, 0x74, 0x65, 0x65, 0x6e, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x69, 0x63, 0x65, 0x44,
+	0x65, 0x6c, 0x74, 0x61, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x31, 0x32, 0x38, 0x42, 0x69,
+	0x74, 0x48, 0x01, 0x52, 0x15, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x53, 0x69,
+	0x78, 0x74, 0x65, 0x65, 0x6e, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x59, 0x0a, 0x17, 0x61, 0x64,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x54, 0x68, 0x69, 0x72, 0x74, 0x79, 0x54, 0x77, 0x6f,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x69, 0x63, 0x65, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x45, 0x6e, 0x63,
+	0x6f, 0x64, 0x65, 0x64, 0x32, 0x35, 0x36, 0x42, 0x69, 0x74, 0x48, 0x01, 0x52, 0x17, 0x61, 0x64,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x54, 0x68, 0x69, 0x72, 0x74, 0x79, 0x54, 0x77, 0x6f,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x42, 0x0a, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75,
+	0x6d, 0x42, 0x16, 0x0a, 0x14, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x5f,
+	0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xa3, 0x01, 0x0a, 0x15, 0x52, 0x69,
+	0x63, 0x65, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x33, 0x32,
+	0x42, 0x69, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x69, 0x63, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x65, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x72, 0x69, 0x63, 0x65,
+	0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x6e, 0x74,
+	0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0c, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x20, 0x0a,
+	0x0b, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0b, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x22,
+	0xa3, 0x01, 0x0a, 0x15, 0x52, 0x69, 0x63, 0x65, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x45, 0x6e, 0x63,
+	0x6f, 0x64, 0x65, 0x64, 0x36, 0x34, 0x42, 0x69, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x69, 0x72,
+	0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x66,
+	0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x69, 0x63,
+	0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0d, 0x72, 0x69, 0x63, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x12,
+	0x22, 0x0a, 0x0c, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x44, 0x61,
+	0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65,
+	0x64, 0x44, 0x61, 0x74, 0x61, 0x22, 0xcc, 0x01, 0x0a, 0x16, 0x52, 0x69, 0x63, 0x65, 0x44, 0x65,
+	0x6c, 0x74, 0x61, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x31, 0x32, 0x38, 0x42, 0x69, 0x74,
+	0x12, 0x22, 0x0a, 0x0c, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x48, 0x69,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x48, 0x69, 0x12, 0x22, 0x0a, 0x0c, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x4c, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x06, 0x52, 0x0c, 0x66, 0x69, 0x72, 0x73,
+	0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4c, 0x6f, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x69, 0x63, 0x65,
+	0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0d, 0x72, 0x69, 0x63, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x12, 0x22,
+	0x0a, 0x0c, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x44, 0x61, 0x74,
+	0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64,
+	0x44, 0x61, 0x74, 0x61, 0x22, 0xd0, 0x02, 0x0a, 0x16, 0x52, 0x69, 0x63, 0x65, 0x44, 0x65, 0x6c,
+	0x74, 0x61, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x32, 0x35, 0x36, 0x42, 0x69, 0x74, 0x12,
+	0x30, 0x0a, 0x13, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x46, 0x69, 0x72,
+	0x73, 0x74, 0x50, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x13, 0x66, 0x69,
+	0x72, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x46, 0x69, 0x72, 0x73, 0x74, 0x50, 0x61, 0x72,
+	0x74, 0x12, 0x32, 0x0a, 0x14, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x53,
+	0x65, 0x63, 0x6f, 0x6e, 0x64, 0x50, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x06, 0x52,
 	0x14, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e,
-	0x64, 0x50, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x06, 0x52, 0x14, 0x66, 0x69, 0x72,
-	0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x50, 0x61, 0x72,
-	0x74, 0x12, 0x30, 0x0a, 0x13, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x54,
-	0x68, 0x69, 0x72, 0x64, 0x50, 0x61, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x06, 0x52, 0x13,
-	0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x54, 0x68, 0x69, 0x72, 0x64, 0x50,
-	0x61, 0x72, 0x74, 0x12, 0x32, 0x0a, 0x14, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75,
-	0x65, 0x46, 0x6f, 0x75, 0x72, 0x74, 0x68, 0x50, 0x61, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x06, 0x52, 0x14, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x46, 0x6f, 0x75,
-	0x72, 0x74, 0x68, 0x50, 0x61, 0x72, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x69, 0x63, 0x65, 0x50,
-	0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d,
-	0x72, 0x69, 0x63, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x12, 0x22, 0x0a,
-	0x0c, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x0c, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e,
-	0x74, 0x12, 0x20, 0x0a, 0x0b, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61,
-	0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x44,
-	0x61, 0x74, 0x61, 0x22, 0x9b, 0x02, 0x0a, 0x10, 0x48, 0x61, 0x73, 0x68, 0x4c, 0x69, 0x73, 0x74,
-	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x33, 0x0a, 0x0b, 0x74, 0x68, 0x72, 0x65,
-	0x61, 0x74, 0x54, 0x79, 0x70, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x11, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x68, 0x72, 0x65, 0x61, 0x74, 0x54, 0x79, 0x70, 0x65,
-	0x52, 0x0b, 0x74, 0x68, 0x72, 0x65, 0x61, 0x74, 0x54, 0x79, 0x70, 0x65, 0x73, 0x12, 0x3f, 0x0a,
-	0x0f, 0x6c, 0x69, 0x6b, 0x65, 0x6c, 0x79, 0x53, 0x61, 0x66, 0x65, 0x54, 0x79, 0x70, 0x65, 0x73,
-	0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c,
-	0x69, 0x6b, 0x65, 0x6c, 0x79, 0x53, 0x61, 0x66, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0f, 0x6c,
-	0x69, 0x6b, 0x65, 0x6c, 0x79, 0x53, 0x61, 0x66, 0x65, 0x54, 0x79, 0x70, 0x65, 0x73, 0x12, 0x28,
-	0x0a, 0x0f, 0x6d, 0x6f, 0x62, 0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6d, 0x69, 0x7a, 0x65,
-	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x6d, 0x6f, 0x62, 0x69, 0x6c, 0x65, 0x4f,
-	0x70, 0x74, 0x69, 0x6d, 0x69, 0x7a, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64,
-	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x45, 0x0a, 0x14, 0x73, 0x75,
-	0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x48, 0x61, 0x73, 0x68, 0x4c, 0x65, 0x6e, 0x67, 0x74,
-	0x68, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x48, 0x61, 0x73, 0x68, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x52, 0x14, 0x73, 0x75, 0x70,
-	0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x48, 0x61, 0x73, 0x68, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68,
-	0x73, 0x2a, 0x8a, 0x01, 0x0a, 0x0a, 0x54, 0x68, 0x72, 0x65, 0x61, 0x74, 0x54, 0x79, 0x70, 0x65,
-	0x12, 0x1b, 0x0a, 0x17, 0x54, 0x48, 0x52, 0x45, 0x41, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
-	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0b, 0x0a,
-	0x07, 0x4d, 0x41, 0x4c, 0x57, 0x41, 0x52, 0x45, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x4f,
-	0x43, 0x49, 0x41, 0x4c, 0x5f, 0x45, 0x4e, 0x47, 0x49, 0x4e, 0x45, 0x45, 0x52, 0x49, 0x4e, 0x47,
-	0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x55, 0x4e, 0x57, 0x41, 0x4e, 0x54, 0x45, 0x44, 0x5f, 0x53,
-	0x4f, 0x46, 0x54, 0x57, 0x41, 0x52, 0x45, 0x10, 0x03, 0x12, 0x23, 0x0a, 0x1f, 0x50, 0x4f, 0x54,
-	0x45, 0x4e, 0x54, 0x49, 0x41, 0x4c, 0x4c, 0x59, 0x5f, 0x48, 0x41, 0x52, 0x4d, 0x46, 0x55, 0x4c,
-	0x5f, 0x41, 0x50, 0x50, 0x4c, 0x49, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x04, 0x2a, 0x5f,
-	0x0a, 0x0e, 0x4c, 0x69, 0x6b, 0x65, 0x6c, 0x79, 0x53, 0x61, 0x66, 0x65, 0x54, 0x79, 0x70, 0x65,
-	0x12, 0x20, 0x0a, 0x1c, 0x4c, 0x49, 0x4b, 0x45, 0x4c, 0x59, 0x5f, 0x53, 0x41, 0x46, 0x45, 0x5f,
+	0x64, 0x50, 0x61, 0x72, 0x74, 0x12, 0x30, 0x0a, 0x13, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x54, 0x68, 0x69, 0x72, 0x64, 0x50, 0x61, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x06, 0x52, 0x13, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x54, 0x68,
+	0x69, 0x72, 0x64, 0x50, 0x61, 0x72, 0x74, 0x12, 0x32, 0x0a, 0x14, 0x66, 0x69, 0x72, 0x73, 0x74,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x46, 0x6f, 0x75, 0x72, 0x74, 0x68, 0x50, 0x61, 0x72, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x06, 0x52, 0x14, 0x66, 0x69, 0x72, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x46, 0x6f, 0x75, 0x72, 0x74, 0x68, 0x50, 0x61, 0x72, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x72,
+	0x69, 0x63, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0d, 0x72, 0x69, 0x63, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65,
+	0x72, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64,
+	0x44, 0x61, 0x74, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x65, 0x6e, 0x63, 0x6f,
+	0x64, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x22, 0x88, 0x01, 0x0a, 0x14, 0x53, 0x65, 0x61, 0x72,
+	0x63, 0x68, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2f, 0x0a, 0x0a, 0x66, 0x75, 0x6c, 0x6c, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x46, 0x75, 0x6c,
+	0x6c, 0x48, 0x61, 0x73, 0x68, 0x52, 0x0a, 0x66, 0x75, 0x6c, 0x6c, 0x48, 0x61, 0x73, 0x68, 0x65,
+	0x73, 0x12, 0x3f, 0x0a, 0x0d, 0x63, 0x61, 0x63, 0x68, 0x65, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x63, 0x61, 0x63, 0x68, 0x65, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0x67, 0x0a, 0x08, 0x46, 0x75, 0x6c, 0x6c, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1a,
+	0x0a, 0x08, 0x66, 0x75, 0x6c, 0x6c, 0x48, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x08, 0x66, 0x75, 0x6c, 0x6c, 0x48, 0x61, 0x73, 0x68, 0x12, 0x3f, 0x0a, 0x0f, 0x66, 0x75,
+	0x6c, 0x6c, 0x48, 0x61, 0x73, 0x68, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x46, 0x75, 0x6c, 0x6c,
+	0x48, 0x61, 0x73, 0x68, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x52, 0x0f, 0x66, 0x75, 0x6c, 0x6c,
+	0x48, 0x61, 0x73, 0x68, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x22, 0x7b, 0x0a, 0x0e, 0x46,
+	0x75, 0x6c, 0x6c, 0x48, 0x61, 0x73, 0x68, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x31, 0x0a,
+	0x0a, 0x74, 0x68, 0x72, 0x65, 0x61, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x68, 0x72, 0x65, 0x61, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x0a, 0x74, 0x68, 0x72, 0x65, 0x61, 0x74, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x36, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x68, 0x72,
+	0x65, 0x61, 0x74, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x52, 0x0a, 0x61, 0x74,
+	0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x22, 0x9b, 0x02, 0x0a, 0x10, 0x48, 0x61, 0x73,
+	0x68, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x33, 0x0a,
+	0x0b, 0x74, 0x68, 0x72, 0x65, 0x61, 0x74, 0x54, 0x79, 0x70, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0e, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x68, 0x72, 0x65, 0x61,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x74, 0x68, 0x72, 0x65, 0x61, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x73, 0x12, 0x3f, 0x0a, 0x0f, 0x6c, 0x69, 0x6b, 0x65, 0x6c, 0x79, 0x53, 0x61, 0x66, 0x65,
+	0x54, 0x79, 0x70, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x6b, 0x65, 0x6c, 0x79, 0x53, 0x61, 0x66, 0x65, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x0f, 0x6c, 0x69, 0x6b, 0x65, 0x6c, 0x79, 0x53, 0x61, 0x66, 0x65, 0x54, 0x79,
+	0x70, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x6f, 0x62, 0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74,
+	0x69, 0x6d, 0x69, 0x7a, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x6d, 0x6f,
+	0x62, 0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6d, 0x69, 0x7a, 0x65, 0x64, 0x12, 0x20, 0x0a,
+	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x45, 0x0a, 0x14, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x48, 0x61, 0x73, 0x68,
+	0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x11, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68,
+	0x52, 0x14, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x48, 0x61, 0x73, 0x68, 0x4c,
+	0x65, 0x6e, 0x67, 0x74, 0x68, 0x73, 0x2a, 0x8a, 0x01, 0x0a, 0x0a, 0x54, 0x68, 0x72, 0x65, 0x61,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x17, 0x54, 0x48, 0x52, 0x45, 0x41, 0x54, 0x5f,
 	0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
-	0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x47, 0x45, 0x4e, 0x45, 0x52, 0x41, 0x4c, 0x5f, 0x42, 0x52,
-	0x4f, 0x57, 0x53, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x53, 0x44, 0x10,
-	0x02, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x4f, 0x57, 0x4e, 0x4c, 0x4f, 0x41, 0x44, 0x10, 0x03, 0x2a,
-	0x73, 0x0a, 0x0a, 0x48, 0x61, 0x73, 0x68, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x1b, 0x0a,
-	0x17, 0x48, 0x41, 0x53, 0x48, 0x5f, 0x4c, 0x45, 0x4e, 0x47, 0x54, 0x48, 0x5f, 0x55, 0x4e, 0x53,
-	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x46, 0x4f,
-	0x55, 0x52, 0x5f, 0x42, 0x59, 0x54, 0x45, 0x53, 0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x45, 0x49,
-	0x47, 0x48, 0x54, 0x5f, 0x42, 0x59, 0x54, 0x45, 0x53, 0x10, 0x03, 0x12, 0x11, 0x0a, 0x0d, 0x53,
-	0x49, 0x58, 0x54, 0x45, 0x45, 0x4e, 0x5f, 0x42, 0x59, 0x54, 0x45, 0x53, 0x10, 0x04, 0x12, 0x14,
-	0x0a, 0x10, 0x54, 0x48, 0x49, 0x52, 0x54, 0x59, 0x5f, 0x54, 0x57, 0x4f, 0x5f, 0x42, 0x59, 0x54,
-	0x45, 0x53, 0x10, 0x05, 0x42, 0x09, 0x5a, 0x07, 0x2e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x4d, 0x41, 0x4c, 0x57, 0x41, 0x52, 0x45, 0x10, 0x01, 0x12,
+	0x16, 0x0a, 0x12, 0x53, 0x4f, 0x43, 0x49, 0x41, 0x4c, 0x5f, 0x45, 0x4e, 0x47, 0x49, 0x4e, 0x45,
+	0x45, 0x52, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x55, 0x4e, 0x57, 0x41, 0x4e,
+	0x54, 0x45, 0x44, 0x5f, 0x53, 0x4f, 0x46, 0x54, 0x57, 0x41, 0x52, 0x45, 0x10, 0x03, 0x12, 0x23,
+	0x0a, 0x1f, 0x50, 0x4f, 0x54, 0x45, 0x4e, 0x54, 0x49, 0x41, 0x4c, 0x4c, 0x59, 0x5f, 0x48, 0x41,
+	0x52, 0x4d, 0x46, 0x55, 0x4c, 0x5f, 0x41, 0x50, 0x50, 0x4c, 0x49, 0x43, 0x41, 0x54, 0x49, 0x4f,
+	0x4e, 0x10, 0x04, 0x2a, 0x4f, 0x0a, 0x0f, 0x54, 0x68, 0x72, 0x65, 0x61, 0x74, 0x41, 0x74, 0x74,
+	0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x12, 0x20, 0x0a, 0x1c, 0x54, 0x48, 0x52, 0x45, 0x41, 0x54,
+	0x5f, 0x41, 0x54, 0x54, 0x52, 0x49, 0x42, 0x55, 0x54, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x41, 0x4e, 0x41,
+	0x52, 0x59, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x46, 0x52, 0x41, 0x4d, 0x45, 0x5f, 0x4f, 0x4e,
+	0x4c, 0x59, 0x10, 0x02, 0x2a, 0x5f, 0x0a, 0x0e, 0x4c, 0x69, 0x6b, 0x65, 0x6c, 0x79, 0x53, 0x61,
+	0x66, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x1c, 0x4c, 0x49, 0x4b, 0x45, 0x4c, 0x59,
+	0x5f, 0x53, 0x41, 0x46, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x47, 0x45, 0x4e, 0x45,
+	0x52, 0x41, 0x4c, 0x5f, 0x42, 0x52, 0x4f, 0x57, 0x53, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x07,
+	0x0a, 0x03, 0x43, 0x53, 0x44, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x4f, 0x57, 0x4e, 0x4c,
+	0x4f, 0x41, 0x44, 0x10, 0x03, 0x2a, 0x73, 0x0a, 0x0a, 0x48, 0x61, 0x73, 0x68, 0x4c, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x12, 0x1b, 0x0a, 0x17, 0x48, 0x41, 0x53, 0x48, 0x5f, 0x4c, 0x45, 0x4e, 0x47,
+	0x54, 0x48, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00,
+	0x12, 0x0e, 0x0a, 0x0a, 0x46, 0x4f, 0x55, 0x52, 0x5f, 0x42, 0x59, 0x54, 0x45, 0x53, 0x10, 0x02,
+	0x12, 0x0f, 0x0a, 0x0b, 0x45, 0x49, 0x47, 0x48, 0x54, 0x5f, 0x42, 0x59, 0x54, 0x45, 0x53, 0x10,
+	0x03, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x49, 0x58, 0x54, 0x45, 0x45, 0x4e, 0x5f, 0x42, 0x59, 0x54,
+	0x45, 0x53, 0x10, 0x04, 0x12, 0x14, 0x0a, 0x10, 0x54, 0x48, 0x49, 0x52, 0x54, 0x59, 0x5f, 0x54,
+	0x57, 0x4f, 0x5f, 0x42, 0x59, 0x54, 0x45, 0x53, 0x10, 0x05, 0x42, 0x09, 0x5a, 0x07, 0x2e, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -730,33 +1207,47 @@ func file_proto_hashlists_proto_rawDescGZIP() []byte {
 	return file_proto_hashlists_proto_rawDescData
 }
 
-var file_proto_hashlists_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_proto_hashlists_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_proto_hashlists_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_proto_hashlists_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_proto_hashlists_proto_goTypes = []any{
 	(ThreatType)(0),                // 0: proto.ThreatType
-	(LikelySafeType)(0),            // 1: proto.LikelySafeType
-	(HashLength)(0),                // 2: proto.HashLength
-	(*ListHashListsResponse)(nil),  // 3: proto.ListHashListsResponse
-	(*HashList)(nil),               // 4: proto.HashList
-	(*RiceDeltaEncoded32Bit)(nil),  // 5: proto.RiceDeltaEncoded32Bit
-	(*RiceDeltaEncoded256Bit)(nil), // 6: proto.RiceDeltaEncoded256Bit
-	(*HashListMetadata)(nil),       // 7: proto.HashListMetadata
-	(*durationpb.Duration)(nil),    // 8: google.protobuf.Duration
+	(ThreatAttribute)(0),           // 1: proto.ThreatAttribute
+	(LikelySafeType)(0),            // 2: proto.LikelySafeType
+	(HashLength)(0),                // 3: proto.HashLength
+	(*ListHashListsResponse)(nil),  // 4: proto.ListHashListsResponse
+	(*HashList)(nil),               // 5: proto.HashList
+	(*RiceDeltaEncoded32Bit)(nil),  // 6: proto.RiceDeltaEncoded32Bit
+	(*RiceDeltaEncoded64Bit)(nil),  // 7: proto.RiceDeltaEncoded64Bit
+	(*RiceDeltaEncoded128Bit)(nil), // 8: proto.RiceDeltaEncoded128Bit
+	(*RiceDeltaEncoded256Bit)(nil), // 9: proto.RiceDeltaEncoded256Bit
+	(*SearchHashesResponse)(nil),   // 10: proto.SearchHashesResponse
+	(*FullHash)(nil),               // 11: proto.FullHash
+	(*FullHashDetail)(nil),         // 12: proto.FullHashDetail
+	(*HashListMetadata)(nil),       // 13: proto.HashListMetadata
+	(*durationpb.Duration)(nil),    // 14: google.protobuf.Duration
 }
 var file_proto_hashlists_proto_depIdxs = []int32{
-	4, // 0: proto.ListHashListsResponse.hashLists:type_name -> proto.HashList
-	5, // 1: proto.HashList.compressedRemovals:type_name -> proto.RiceDeltaEncoded32Bit
-	8, // 2: proto.HashList.minimumWaitDuration:type_name -> google.protobuf.Duration
-	7, // 3: proto.HashList.metadata:type_name -> proto.HashListMetadata
-	6, // 4: proto.HashList.additionsThirtyTwoBytes:type_name -> proto.RiceDeltaEncoded256Bit
-	0, // 5: proto.HashListMetadata.threatTypes:type_name -> proto.ThreatType
-	1, // 6: proto.HashListMetadata.likelySafeTypes:type_name -> proto.LikelySafeType
-	2, // 7: proto.HashListMetadata.supportedHashLengths:type_name -> proto.HashLength
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	5,  // 0: proto.ListHashListsResponse.hashLists:type_name -> proto.HashList
+	6,  // 1: proto.HashList.compressedRemovals:type_name -> proto.RiceDeltaEncoded32Bit
+	14, // 2: proto.HashList.minimumWaitDuration:type_name -> google.protobuf.Duration
+	13, // 3: proto.HashList.metadata:type_name -> proto.HashListMetadata
+	6,  // 4: proto.HashList.additionsFourBytes:type_name -> proto.RiceDeltaEncoded32Bit
+	7,  // 5: proto.HashList.additionsEightBytes:type_name -> proto.RiceDeltaEncoded64Bit
+	8,  // 6: proto.HashList.additionsSixteenBytes:type_name -> proto.RiceDeltaEncoded128Bit
+	9,  // 7: proto.HashList.additionsThirtyTwoBytes:type_name -> proto.RiceDeltaEncoded256Bit
+	11, // 8: proto.SearchHashesResponse.fullHashes:type_name -> proto.FullHash
+	14, // 9: proto.SearchHashesResponse.cacheDuration:type_name -> google.protobuf.Duration
+	12, // 10: proto.FullHash.fullHashDetails:type_name -> proto.FullHashDetail
+	0,  // 11: proto.FullHashDetail.threatType:type_name -> proto.ThreatType
+	1,  // 12: proto.FullHashDetail.attributes:type_name -> proto.ThreatAttribute
+	0,  // 13: proto.HashListMetadata.threatTypes:type_name -> proto.ThreatType
+	2,  // 14: proto.HashListMetadata.likelySafeTypes:type_name -> proto.LikelySafeType
+	3,  // 15: proto.HashListMetadata.supportedHashLengths:type_name -> proto.HashLength
+	16, // [16:16] is the sub-list for method output_type
+	16, // [16:16] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_proto_hashlists_proto_init() }
@@ -766,6 +1257,9 @@ func file_proto_hashlists_proto_init() {
 	}
 	file_proto_hashlists_proto_msgTypes[1].OneofWrappers = []any{
 		(*HashList_Sha256Checksum)(nil),
+		(*HashList_AdditionsFourBytes)(nil),
+		(*HashList_AdditionsEightBytes)(nil),
+		(*HashList_AdditionsSixteenBytes)(nil),
 		(*HashList_AdditionsThirtyTwoBytes)(nil),
 	}
 	type x struct{}
@@ -773,8 +1267,8 @@ func file_proto_hashlists_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_hashlists_proto_rawDesc,
-			NumEnums:      3,
-			NumMessages:   5,
+			NumEnums:      4,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,103 @@
+package safebrowsing
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// defaultFullHashCacheSize is how many hash prefixes the full-hash cache keeps by default.
+const defaultFullHashCacheSize = 10000
+
+// fullHashCache keeps the server responses about hash prefixes for their cache duration, so a prefix is not
+// searched again on every check. Prefixes without full hashes are cached too, as the server reported them clean.
+// The least recently used prefixes are evicted when the cache is full.
+type fullHashCache struct {
+	lock     sync.Mutex
+	capacity int
+	entries  map[[4]byte]*list.Element
+	// order has the most recently used entries at the front.
+	order  *list.List
+	hits   uint64
+	misses uint64
+}
+
+type fullHashCacheEntry struct {
+	prefix [4]byte
+	result searchResult
+}
+
+// CacheStats are the counters of the full-hash cache.
+type CacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+}
+
+func newFullHashCache(capacity int) *fullHashCache {
+	return &fullHashCache{
+		capacity: capacity,
+		entries:  make(map[[4]byte]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// get returns the cached result of the prefix unless it's expired.
+func (c *fullHashCache) get(prefix [4]byte, now time.Time) (searchResult, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[prefix]
+	if !ok {
+		c.misses++
+		return searchResult{}, false
+	}
+
+	entry := element.Value.(*fullHashCacheEntry)
+	if !now.Before(entry.result.expiry) {
+		c.order.Remove(element)
+		delete(c.entries, prefix)
+		c.misses++
+		return searchResult{}, false
+	}
+
+	c.order.MoveToFront(element)
+	c.hits++
+
+	return entry.result, true
+}
+
+// put caches the result of the prefix until its expiry. Results without an expiry are not cached.
+func (c *fullHashCache) put(prefix [4]byte, result searchResult) {
+	if c.capacity <= 0 || result.expiry.IsZero() {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if element, ok := c.entries[prefix]; ok {
+		element.Value.(*fullHashCacheEntry).result = result
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[prefix] = c.order.PushFront(&fullHashCacheEntry{prefix: prefix, result: result})
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*fullHashCacheEntry).prefix)
+	}
+}
+
+func (c *fullHashCache) stats() CacheStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return CacheStats{
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Entries: c.order.Len(),
+	}
+}
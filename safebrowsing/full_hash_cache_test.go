@@ -0,0 +1,81 @@
+package safebrowsing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JILeXanDR/gsb-v5-tests/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_fullHashCache(t *testing.T) {
+	now := time.Now()
+	positive := searchResult{
+		fullHashes: []*proto.FullHash{newStubFullHash("evil.example.com/", proto.ThreatType_MALWARE)},
+		expiry:     now.Add(time.Minute),
+	}
+	negative := searchResult{expiry: now.Add(time.Minute)}
+
+	t.Run("positive and negative entries", func(t *testing.T) {
+		cache := newFullHashCache(10)
+		cache.put([4]byte{1}, positive)
+		cache.put([4]byte{2}, negative)
+
+		result, ok := cache.get([4]byte{1}, now)
+		require.True(t, ok)
+		assert.Equal(t, positive, result)
+
+		result, ok = cache.get([4]byte{2}, now)
+		require.True(t, ok)
+		assert.Empty(t, result.fullHashes)
+
+		_, ok = cache.get([4]byte{3}, now)
+		assert.False(t, ok)
+
+		assert.Equal(t, CacheStats{Hits: 2, Misses: 1, Entries: 2}, cache.stats())
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		cache := newFullHashCache(10)
+		cache.put([4]byte{1}, positive)
+		cache.put([4]byte{2}, searchResult{})
+
+		_, ok := cache.get([4]byte{1}, positive.expiry)
+		assert.False(t, ok)
+
+		_, ok = cache.get([4]byte{2}, now)
+		assert.False(t, ok, "results without cache duration must not be cached")
+
+		assert.Equal(t, CacheStats{Misses: 2}, cache.stats())
+	})
+
+	t.Run("least recently used are evicted", func(t *testing.T) {
+		cache := newFullHashCache(2)
+		cache.put([4]byte{1}, negative)
+		cache.put([4]byte{2}, negative)
+
+		_, ok := cache.get([4]byte{1}, now)
+		require.True(t, ok)
+
+		cache.put([4]byte{3}, negative)
+
+		_, ok = cache.get([4]byte{2}, now)
+		assert.False(t, ok)
+
+		for _, prefix := range [][4]byte{{1}, {3}} {
+			_, ok = cache.get(prefix, now)
+			assert.True(t, ok)
+		}
+
+		assert.Equal(t, 2, cache.stats().Entries)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		cache := newFullHashCache(0)
+		cache.put([4]byte{1}, positive)
+
+		_, ok := cache.get([4]byte{1}, now)
+		assert.False(t, ok)
+	})
+}
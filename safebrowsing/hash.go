@@ -0,0 +1,9 @@
+package safebrowsing
+
+import (
+	"crypto/sha256"
+)
+
+func hashFull(input string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(input))
+}
@@ -0,0 +1,731 @@
+// Package safebrowsing checks URLs against the Google Safe Browsing v5 lists. The lists are kept locally and only
+// the hash prefixes found in them are confirmed with the server.
+package safebrowsing
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/JILeXanDR/gsb-v5-tests/internal/api"
+	"github.com/JILeXanDR/gsb-v5-tests/internal/backoff"
+	"github.com/JILeXanDR/gsb-v5-tests/internal/database"
+	"github.com/JILeXanDR/gsb-v5-tests/internal/logging"
+	"github.com/JILeXanDR/gsb-v5-tests/internal/urls"
+	"github.com/JILeXanDR/gsb-v5-tests/proto"
+)
+
+type CheckResult struct {
+	// URL is the checked URL as it was given.
+	URL string
+	// CanonicalURL is the URL after canonicalization, the expressions are generated from it.
+	CanonicalURL string
+	Safe         bool
+	Threats      []proto.ThreatType
+	// LikelySafeTypes are set when expressions of the URL are found in the global cache. Those expressions are not
+	// checked against the threat lists, the other expressions of the URL are.
+	LikelySafeTypes []proto.LikelySafeType
+	// Err is set when the URL couldn't be checked. Safe is then decided by the failure policy.
+	Err error
+	// Matches are the expressions of the URL found in the threat lists. A URL is unsafe only if the full hash of
+	// one of them is confirmed by the server, the others are prefix collisions.
+	Matches []Match
+}
+
+// Match explains why an expression of the URL was considered a threat.
+type Match struct {
+	// Expression is the host suffix and path prefix combination whose hash matched, e.g. "example.com/1/".
+	Expression string
+	// ThreatTypes are the threats of the confirmed full hash.
+	ThreatTypes []proto.ThreatType
+	// Attributes qualify the threats, e.g. FRAME_ONLY threats should only be enforced on frames. Threats with the
+	// CANARY attribute must not be enforced at all, so they are left out of the match.
+	Attributes []proto.ThreatAttribute
+	// Lists are the local lists the hash prefix of the expression was found in.
+	Lists []MatchedList
+	// FullHashConfirmed is whether the server returned the full hash of the expression. Otherwise only the hash
+	// prefix matched, and the expression is not a threat.
+	FullHashConfirmed bool
+	// CacheExpiry is until when the server response about the hash prefix may be cached.
+	CacheExpiry time.Time
+}
+
+// MatchedList is a local list with the version the match was found in.
+type MatchedList struct {
+	Name    string
+	Version []byte
+}
+
+// InvalidURLError is reported for URLs which can't be canonicalized.
+type InvalidURLError struct {
+	URL string
+	Err error
+}
+
+func (e *InvalidURLError) Error() string {
+	return fmt.Sprintf("invalid URL %q: %v", e.URL, e.Err)
+}
+
+func (e *InvalidURLError) Unwrap() error {
+	return e.Err
+}
+
+// FailurePolicy decides the verdict of a URL which couldn't be checked.
+type FailurePolicy int
+
+const (
+	// FailurePolicyError leaves the URL without a verdict: the result is not safe, has the error and CheckURLs
+	// returns the errors of all such URLs along with the results.
+	FailurePolicyError FailurePolicy = iota
+	// FailurePolicyOpen considers the URL safe.
+	FailurePolicyOpen
+	// FailurePolicyClosed considers the URL unsafe.
+	FailurePolicyClosed
+)
+
+// API is the Safe Browsing API client, it can be replaced with WithAPIClient.
+type API = api.API
+
+// Logger is where the library writes its logs. *log.Logger satisfies it.
+type Logger = logging.Logger
+
+// APIError is a failed response of the Safe Browsing API, decoded from the google.rpc.Status in its body. It's
+// returned, possibly wrapped, by NewSafeBrowser and CheckURLs. Use errors.As to inspect it, or errors.Is to match it
+// against ErrInvalidAPIKey, ErrQuotaExceeded, ErrPermissionDenied and ErrUnavailable.
+type APIError = api.Error
+
+// APIErrorDetail is a detail of an APIError, e.g. the reason of the error.
+type APIErrorDetail = api.ErrorDetail
+
+var (
+	// ErrInvalidAPIKey matches the API errors of a missing, invalid, revoked or expired API key.
+	ErrInvalidAPIKey = api.ErrInvalidAPIKey
+	// ErrQuotaExceeded matches the API errors of rate limited requests.
+	ErrQuotaExceeded = api.ErrQuotaExceeded
+	// ErrPermissionDenied matches the API errors of requests the key is not allowed to make.
+	ErrPermissionDenied = api.ErrPermissionDenied
+	// ErrUnavailable matches the API errors of requests the API failed to serve.
+	ErrUnavailable = api.ErrUnavailable
+	// ErrBackingOff is returned for the URLs which need the server while the client is in the back-off mode after
+	// failed requests. Their verdict is decided by the degraded policy.
+	ErrBackingOff = errors.New("backing off after failed requests")
+)
+
+type SafeBrowserOption func(*safeBrowserOptions)
+
+type safeBrowserOptions struct {
+	key            string
+	api            API
+	databasePath   string
+	databaseMaxAge time.Duration
+	expressions    urls.Options
+	invalidURLs    FailurePolicy
+	degraded       FailurePolicy
+	concurrency    int
+	fullHashCache  int
+	httpClient     *http.Client
+	transport      http.RoundTripper
+	baseURL        string
+	requestTimeout time.Duration
+	logger         Logger
+}
+
+// clientOptions configures the API client created when none is set with WithAPIClient.
+func (o *safeBrowserOptions) clientOptions(logger Logger) []api.ClientOption {
+	clientOptions := []api.ClientOption{api.WithTimeout(o.requestTimeout), api.WithLogger(logger)}
+
+	if o.baseURL != "" {
+		clientOptions = append(clientOptions, api.WithBaseURL(o.baseURL))
+	}
+
+	httpClient := o.httpClient
+
+	if o.transport != nil {
+		withTransport := &http.Client{}
+		if httpClient != nil {
+			*withTransport = *httpClient
+		}
+		withTransport.Transport = o.transport
+		httpClient = withTransport
+	}
+
+	if httpClient != nil {
+		clientOptions = append(clientOptions, api.WithHTTPClient(httpClient))
+	}
+
+	return clientOptions
+}
+
+// defaultDatabaseMaxAge is how old a loaded database may be to be used without waiting for an update.
+const defaultDatabaseMaxAge = time.Hour
+
+func WithAPIKey(key string) SafeBrowserOption {
+	return func(options *safeBrowserOptions) {
+		options.key = key
+	}
+}
+
+func WithAPIClient(api API) SafeBrowserOption {
+	return func(options *safeBrowserOptions) {
+		options.api = api
+	}
+}
+
+// WithHTTPClient sets the client the API requests are sent with, e.g. one configured for a proxy. It's ignored
+// when the API is set with WithAPIClient.
+func WithHTTPClient(httpClient *http.Client) SafeBrowserOption {
+	return func(options *safeBrowserOptions) {
+		options.httpClient = httpClient
+	}
+}
+
+// WithTransport sets the transport the API requests are sent with, e.g. one with client certificates. When an HTTP
+// client is set too, the transport replaces the one of a copy of it, so the given client is left untouched.
+func WithTransport(transport http.RoundTripper) SafeBrowserOption {
+	return func(options *safeBrowserOptions) {
+		options.transport = transport
+	}
+}
+
+// WithBaseURL sets the address of the Safe Browsing API, e.g. of a test server. The default is api.DefaultBaseURL.
+func WithBaseURL(baseURL string) SafeBrowserOption {
+	return func(options *safeBrowserOptions) {
+		options.baseURL = baseURL
+	}
+}
+
+// WithRequestTimeout limits every API request. The default is api.DefaultTimeout, zero disables the limit. It also
+// limits the update of the local database in NewSafeBrowser as a whole, which downloads the complete lists on the
+// first start and is the largest request.
+func WithRequestTimeout(timeout time.Duration) SafeBrowserOption {
+	return func(options *safeBrowserOptions) {
+		options.requestTimeout = timeout
+	}
+}
+
+// WithLogger sets the logger of the library instead of the standard logger. The API key is redacted from every
+// message before it's passed to the logger.
+func WithLogger(logger Logger) SafeBrowserOption {
+	return func(options *safeBrowserOptions) {
+		options.logger = logger
+	}
+}
+
+// WithDatabasePath enables persisting the local database to the file. The database is loaded from it at start,
+// so the lists are not downloaded again, and is saved after each update. Processes on a host may share the path: the
+// file is memory-mapped, and a file replaced by another process is mapped again before updating, so they share one
+// copy of the lists.
+func WithDatabasePath(path string) SafeBrowserOption {
+	return func(options *safeBrowserOptions) {
+		options.databasePath = path
+	}
+}
+
+// WithDatabaseMaxAge sets how old the loaded database may be to skip the initial update. An older database is still
+// used as a base for an incremental update.
+func WithDatabaseMaxAge(maxAge time.Duration) SafeBrowserOption {
+	return func(options *safeBrowserOptions) {
+		options.databaseMaxAge = maxAge
+	}
+}
+
+// WithETLDPlusOneHostSuffixes stops host suffixes at the registrable domain according to the public suffix list,
+// instead of going down to the last two components as the specification says. E.g. "co.uk" is not looked up for
+// "example.co.uk".
+func WithETLDPlusOneHostSuffixes() SafeBrowserOption {
+	return func(options *safeBrowserOptions) {
+		options.expressions.ETLDPlusOneHostSuffixes = true
+	}
+}
+
+// WithInvalidURLPolicy sets the verdict of URLs which can't be canonicalized. FailurePolicyError is the default.
+func WithInvalidURLPolicy(policy FailurePolicy) SafeBrowserOption {
+	return func(options *safeBrowserOptions) {
+		options.invalidURLs = policy
+	}
+}
+
+// WithDegradedPolicy sets the verdict of URLs which weren't checked before the context was done, e.g. because
+// the server didn't confirm their hash prefixes in time. URLs which don't need the server are checked regardless.
+// FailurePolicyError is the default.
+func WithDegradedPolicy(policy FailurePolicy) SafeBrowserOption {
+	return func(options *safeBrowserOptions) {
+		options.degraded = policy
+	}
+}
+
+// WithConcurrency sets how many goroutines a batch of URLs is processed by. It's GOMAXPROCS by default.
+func WithConcurrency(concurrency int) SafeBrowserOption {
+	return func(options *safeBrowserOptions) {
+		options.concurrency = concurrency
+	}
+}
+
+// WithFullHashCacheSize sets how many hash prefixes the server responses are cached for. Zero disables the cache.
+func WithFullHashCacheSize(size int) SafeBrowserOption {
+	return func(options *safeBrowserOptions) {
+		options.fullHashCache = size
+	}
+}
+
+type SafeBrowser struct {
+	api               API
+	localDatabase     localDatabase
+	expressionOptions urls.Options
+	invalidURLPolicy  FailurePolicy
+	degradedPolicy    FailurePolicy
+	concurrency       int
+	fullHashCache     *fullHashCache
+	// backoff is shared with the list updates of the local database.
+	backoff *backoff.Backoff
+}
+
+func NewSafeBrowser(options ...SafeBrowserOption) (*SafeBrowser, error) {
+	opts := &safeBrowserOptions{
+		databaseMaxAge: defaultDatabaseMaxAge,
+		concurrency:    runtime.GOMAXPROCS(0),
+		fullHashCache:  defaultFullHashCacheSize,
+		requestTimeout: api.DefaultTimeout,
+		logger:         logging.Default(),
+	}
+
+	for _, option := range options {
+		option(opts)
+	}
+
+	logger := logging.Redacting(opts.logger, opts.key)
+
+	client := opts.api
+
+	if client == nil {
+		apiClient, err := api.NewClient(opts.key, opts.clientOptions(logger)...)
+		if err != nil {
+			return nil, err
+		}
+		client = apiClient
+	}
+
+	ctx := context.Background()
+
+	if opts.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.requestTimeout)
+		defer cancel()
+	}
+
+	db, err := database.Open(ctx, client, logger, opts.databasePath, opts.databaseMaxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	sb := &SafeBrowser{
+		api:               client,
+		localDatabase:     db,
+		expressionOptions: opts.expressions,
+		invalidURLPolicy:  opts.invalidURLs,
+		degradedPolicy:    opts.degraded,
+		concurrency:       opts.concurrency,
+		fullHashCache:     newFullHashCache(opts.fullHashCache),
+		backoff:           db.Backoff(),
+	}
+
+	return sb, nil
+}
+
+func (sb *SafeBrowser) Run(ctx context.Context) {
+	sb.localDatabase.RunSelfUpdates(ctx)
+}
+
+// Close releases the local database. The SafeBrowser must not be used afterwards.
+func (sb *SafeBrowser) Close() error {
+	return sb.localDatabase.Close()
+}
+
+// CheckURLs checks the URLs and returns their results in the same order. A URL which can't be checked doesn't fail
+// the others: its result has the error and its verdict is decided by a failure policy. Invalid URLs follow the policy
+// set with WithInvalidURLPolicy, URLs left unchecked when the context is done or the server fails follow the one set
+// with WithDegradedPolicy. With FailurePolicyError the errors of such URLs are also returned joined, along with all
+// the results. Errors of the server are returned along with the results with any policy.
+//
+// The URLs are checked as a batch: expressions and hash prefixes shared by URLs are hashed, looked up and searched
+// only once.
+func (sb *SafeBrowser) CheckURLs(ctx context.Context, rawURLs []string) ([]CheckResult, error) {
+	results := make([]CheckResult, len(rawURLs))
+	checks := make([]urlCheck, len(rawURLs))
+
+	for i, rawURL := range rawURLs {
+		results[i].URL = rawURL
+	}
+
+	// Canonicalize the URLs and generate their expressions
+	sb.forEach(ctx, len(rawURLs), func(i int) {
+		canonical, err := urls.Parse(rawURLs[i])
+		if err != nil {
+			checks[i].err = &InvalidURLError{URL: rawURLs[i], Err: err}
+			return
+		}
+
+		results[i].CanonicalURL = canonical.String()
+		checks[i].expressions = canonical.Expressions(sb.expressionOptions)
+	})
+
+	var errs []error
+
+	for i, check := range checks {
+		if check.err == nil {
+			continue
+		}
+
+		results[i].Safe = sb.invalidURLPolicy == FailurePolicyOpen
+		results[i].Err = check.err
+		checks[i].done = true
+
+		if sb.invalidURLPolicy == FailurePolicyError {
+			errs = append(errs, check.err)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return results, errors.Join(append(errs, sb.degrade(results, checks, err))...)
+	}
+
+	// Deduplicate the expressions, URLs of the same host share most of them
+	var expressions []string
+	expressionIndexes := make(map[string]int)
+
+	for i := range checks {
+		checks[i].hashIndexes = make([]int, len(checks[i].expressions))
+
+		for j, expression := range checks[i].expressions {
+			k, ok := expressionIndexes[expression]
+			if !ok {
+				k = len(expressions)
+				expressionIndexes[expression] = k
+				expressions = append(expressions, expression)
+			}
+
+			checks[i].hashIndexes[j] = k
+		}
+	}
+
+	hashes := make([][sha256.Size]byte, len(expressions))
+
+	sb.forEach(ctx, len(expressions), func(i int) {
+		hashes[i] = hashFull(expressions[i])
+	})
+
+	if err := ctx.Err(); err != nil {
+		return results, errors.Join(append(errs, sb.degrade(results, checks, err))...)
+	}
+
+	lookups := sb.localDatabase.LookupHashes(hashes)
+
+	var prefixes [][4]byte
+	seenPrefixes := make(map[[4]byte]struct{})
+
+	for i, check := range checks {
+		if check.done {
+			continue
+		}
+
+		result := &results[i]
+
+		for _, k := range check.hashIndexes {
+			// An expression found in the global cache is safe for general browsing, so it's not checked against the
+			// threat lists. The other expressions of the URL still are: the global cache has popular hosts, and
+			// a page on them may still be a threat.
+			if slices.Contains(lookups[k].LikelySafeTypes, proto.LikelySafeType_GENERAL_BROWSING) {
+				for _, likelySafeType := range lookups[k].LikelySafeTypes {
+					if !slices.Contains(result.LikelySafeTypes, likelySafeType) {
+						result.LikelySafeTypes = append(result.LikelySafeTypes, likelySafeType)
+					}
+				}
+				continue
+			}
+
+			if len(lookups[k].ThreatLists) == 0 {
+				continue
+			}
+
+			match := Match{Expression: expressions[k]}
+			for _, list := range lookups[k].ThreatLists {
+				match.Lists = append(match.Lists, MatchedList{Name: list.Name, Version: list.Version})
+			}
+
+			result.Matches = append(result.Matches, match)
+			checks[i].matchIndexes = append(checks[i].matchIndexes, k)
+
+			prefix := [4]byte(hashes[k][:4])
+			if _, ok := seenPrefixes[prefix]; !ok {
+				seenPrefixes[prefix] = struct{}{}
+				prefixes = append(prefixes, prefix)
+			}
+		}
+
+		// URLs without matches are safe without asking the server
+		if len(result.Matches) == 0 {
+			result.Safe = true
+			checks[i].done = true
+		}
+	}
+
+	if len(prefixes) == 0 {
+		return results, errors.Join(errs...)
+	}
+
+	// The matched URLs are only possibly unsafe here, because different expressions can share the same hash prefix.
+	searchResults, err := sb.findFullHashes(ctx, prefixes)
+	if err != nil {
+		// The URLs which need the server get the degraded verdict, the others keep theirs
+		degradedErr := sb.degrade(results, checks, err)
+
+		// A server failure is reported whatever the policy, a missed deadline only as the policy says
+		if ctxErr := ctx.Err(); ctxErr == nil || !errors.Is(err, ctxErr) {
+			degradedErr = err
+		}
+
+		return results, errors.Join(append(errs, degradedErr)...)
+	}
+
+	for i, check := range checks {
+		if check.done {
+			continue
+		}
+
+		for j, k := range check.matchIndexes {
+			confirmMatch(&results[i].Matches[j], hashes[k], searchResults[[4]byte(hashes[k][:4])])
+		}
+
+		for _, match := range results[i].Matches {
+			for _, threatType := range match.ThreatTypes {
+				if !slices.Contains(results[i].Threats, threatType) {
+					results[i].Threats = append(results[i].Threats, threatType)
+				}
+			}
+		}
+
+		results[i].Safe = len(results[i].Threats) == 0
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// degrade gives the degraded verdict to the URLs which weren't checked before the context was done or the server
+// failed. It returns the error if the policy reports it.
+func (sb *SafeBrowser) degrade(results []CheckResult, checks []urlCheck, err error) error {
+	for i := range checks {
+		if checks[i].done {
+			continue
+		}
+
+		results[i].Safe = sb.degradedPolicy == FailurePolicyOpen
+		results[i].Err = err
+		checks[i].done = true
+	}
+
+	if sb.degradedPolicy == FailurePolicyError {
+		return err
+	}
+
+	return nil
+}
+
+// localDatabase is the part of the local database the SafeBrowser uses.
+type localDatabase interface {
+	RunSelfUpdates(ctx context.Context)
+	LookupHashes(hashes [][sha256.Size]byte) []database.HashLookup
+	Close() error
+}
+
+// urlCheck is the state of a URL within a batch. The indexes point to the deduplicated expressions of the batch.
+type urlCheck struct {
+	expressions  []string
+	hashIndexes  []int
+	matchIndexes []int
+	err          error
+	// done is set once the URL has its verdict.
+	done bool
+}
+
+// searchResult is the server response about a hash prefix.
+type searchResult struct {
+	fullHashes []*proto.FullHash
+	expiry     time.Time
+}
+
+// findFullHashes returns the full hashes of the prefixes from the cache, and searches those which are not cached.
+func (sb *SafeBrowser) findFullHashes(ctx context.Context, prefixes [][4]byte) (map[[4]byte]searchResult, error) {
+	results := make(map[[4]byte]searchResult, len(prefixes))
+
+	var missing [][4]byte
+	now := time.Now()
+
+	for _, prefix := range prefixes {
+		if result, ok := sb.fullHashCache.get(prefix, now); ok {
+			results[prefix] = result
+		} else {
+			missing = append(missing, prefix)
+		}
+	}
+
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	// No request is sent in the back-off mode, the prefixes are left unsearched until it's over
+	if remaining := sb.backoff.Remaining(); remaining > 0 {
+		return nil, fmt.Errorf("%w, retrying in %s", ErrBackingOff, remaining.Round(time.Second))
+	}
+
+	searched, err := sb.searchHashPrefixes(ctx, missing)
+	if err != nil {
+		// Only failures of the server enter the back-off mode, not a done context of the caller
+		if ctx.Err() == nil && api.IsRetryable(err) {
+			sb.backoff.Fail()
+		}
+		return nil, err
+	}
+
+	sb.backoff.Reset()
+
+	for prefix, result := range searched {
+		sb.fullHashCache.put(prefix, result)
+		results[prefix] = result
+	}
+
+	return results, nil
+}
+
+// CacheStats returns the counters of the full-hash cache.
+func (sb *SafeBrowser) CacheStats() CacheStats {
+	return sb.fullHashCache.stats()
+}
+
+// maxHashPrefixesPerSearch splits the hash prefixes of large batches into several requests, which are sent
+// concurrently.
+const maxHashPrefixesPerSearch = 100
+
+// searchHashPrefixes searches the full hashes of the prefixes and groups them by prefix. Every prefix has a result,
+// prefixes without full hashes are safe until the expiry.
+func (sb *SafeBrowser) searchHashPrefixes(ctx context.Context, prefixes [][4]byte) (map[[4]byte]searchResult, error) {
+	chunks := slices.Collect(slices.Chunk(prefixes, maxHashPrefixesPerSearch))
+	responses := make([]*proto.SearchHashesResponse, len(chunks))
+	errs := make([]error, len(chunks))
+
+	sb.forEach(ctx, len(chunks), func(i int) {
+		hashPrefixes := make([][]byte, len(chunks[i]))
+		for j := range chunks[i] {
+			hashPrefixes[j] = chunks[i][j][:]
+		}
+
+		responses[i], _, errs[i] = sb.api.V5alpha1HashesSearch(ctx, hashPrefixes)
+	})
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	// Chunks are left unsearched only when the context is done. A context done after all the chunks were searched
+	// doesn't discard their results.
+	if slices.Contains(responses, nil) {
+		return nil, ctx.Err()
+	}
+
+	results := make(map[[4]byte]searchResult, len(prefixes))
+
+	for i, chunk := range chunks {
+		var expiry time.Time
+		if responses[i].CacheDuration != nil {
+			expiry = time.Now().Add(responses[i].CacheDuration.AsDuration())
+		}
+
+		for _, prefix := range chunk {
+			results[prefix] = searchResult{expiry: expiry}
+		}
+
+		for _, fullHash := range responses[i].FullHashes {
+			if len(fullHash.FullHash) < 4 {
+				continue
+			}
+
+			prefix := [4]byte(fullHash.FullHash[:4])
+
+			result, ok := results[prefix]
+			if !ok {
+				continue
+			}
+
+			result.fullHashes = append(result.fullHashes, fullHash)
+			results[prefix] = result
+		}
+	}
+
+	return results, nil
+}
+
+// confirmMatch fills the match with the threats of its full hash if the server returned it. Canary threats are
+// skipped.
+func confirmMatch(match *Match, hash [sha256.Size]byte, result searchResult) {
+	match.CacheExpiry = result.expiry
+
+	for _, fullHash := range result.fullHashes {
+		if !bytes.Equal(hash[:], fullHash.FullHash) {
+			continue
+		}
+
+		match.FullHashConfirmed = true
+
+		for _, detail := range fullHash.FullHashDetails {
+			if slices.Contains(detail.Attributes, proto.ThreatAttribute_CANARY) {
+				continue
+			}
+
+			if !slices.Contains(match.ThreatTypes, detail.ThreatType) {
+				match.ThreatTypes = append(match.ThreatTypes, detail.ThreatType)
+			}
+
+			for _, attribute := range detail.Attributes {
+				if !slices.Contains(match.Attributes, attribute) {
+					match.Attributes = append(match.Attributes, attribute)
+				}
+			}
+		}
+	}
+}
+
+// forEach calls the function for every index from at most the configured number of goroutines. It stops early when
+// the context is done, so some indexes may be left unprocessed.
+func (sb *SafeBrowser) forEach(ctx context.Context, count int, fn func(i int)) {
+	workers := min(max(sb.concurrency, 1), count)
+	if workers <= 1 {
+		for i := 0; i < count && ctx.Err() == nil; i++ {
+			fn(i)
+		}
+		return
+	}
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+
+	wg.Add(workers)
+
+	for range workers {
+		go func() {
+			defer wg.Done()
+
+			for i := int(next.Add(1) - 1); i < count && ctx.Err() == nil; i = int(next.Add(1) - 1) {
+				fn(i)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,984 @@
+package safebrowsing
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JILeXanDR/gsb-v5-tests/internal/backoff"
+	"github.com/JILeXanDR/gsb-v5-tests/internal/database"
+	"github.com/JILeXanDR/gsb-v5-tests/internal/urls"
+	"github.com/JILeXanDR/gsb-v5-tests/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	proto2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestSafeBrowser_CheckURLs(t *testing.T) {
+	api := &stubAPI{
+		fullHashes: []*proto.FullHash{
+			newStubFullHash("testsafebrowsing.appspot.com/s/phishing.html", proto.ThreatType_SOCIAL_ENGINEERING),
+			newStubFullHash("testsafebrowsing.appspot.com/s/malware.html", proto.ThreatType_MALWARE),
+			newStubFullHash("testsafebrowsing.appspot.com/s/unwanted.html", proto.ThreatType_UNWANTED_SOFTWARE),
+			newStubFullHash("news-xnifepo.cc/", proto.ThreatType_SOCIAL_ENGINEERING),
+			newStubFullHash("phdelaware.com/", proto.ThreatType_SOCIAL_ENGINEERING),
+		},
+	}
+
+	sb := newStubSafeBrowser(api,
+		stubList{
+			name:            "gc",
+			prefixes:        hashPrefixesOf(16, "google.com/"),
+			likelySafeTypes: []proto.LikelySafeType{proto.LikelySafeType_GENERAL_BROWSING},
+		},
+		stubList{
+			name: "se",
+			// The prefix of testsafebrowsing.appspot.com/s/ has no full hash, like a prefix collision.
+			prefixes: hashPrefixesOf(4,
+				"testsafebrowsing.appspot.com/s/phishing.html",
+				"testsafebrowsing.appspot.com/s/",
+				"news-xnifepo.cc/",
+				"phdelaware.com/",
+			),
+			threatTypes: []proto.ThreatType{proto.ThreatType_SOCIAL_ENGINEERING},
+		},
+		stubList{
+			name:        "mw",
+			prefixes:    hashPrefixesOf(4, "testsafebrowsing.appspot.com/s/malware.html"),
+			threatTypes: []proto.ThreatType{proto.ThreatType_MALWARE},
+		},
+		stubList{
+			name:        "uws",
+			prefixes:    hashPrefixesOf(4, "testsafebrowsing.appspot.com/s/unwanted.html"),
+			threatTypes: []proto.ThreatType{proto.ThreatType_UNWANTED_SOFTWARE},
+		},
+	)
+
+	tests := []struct {
+		input  string
+		isSafe bool
+	}{
+		{
+			input:  "https://testsafebrowsing.appspot.com/s/phishing.html",
+			isSafe: false,
+		},
+		{
+			input:  "https://sub.testsafebrowsing.appspot.com/s/phishing.html",
+			isSafe: false,
+		},
+		{
+			input:  "https://sub.testsafebrowsing.appspot.com/s/",
+			isSafe: true,
+		},
+		{
+			input:  "https://testsafebrowsing.appspot.com/s/",
+			isSafe: true,
+		},
+		{
+			input:  "https://testsafebrowsing.appspot.com/s/malware.html",
+			isSafe: false,
+		},
+		{
+			input:  "https://testsafebrowsing.appspot.com/s/unwanted.html",
+			isSafe: false,
+		},
+		{
+			input:  "https://005d975e0e.news-xnifepo.cc",
+			isSafe: false,
+		},
+		{
+			input:  "https://example.com",
+			isSafe: true,
+		},
+		{
+			input:  "https://phdelaware.com/",
+			isSafe: false,
+		},
+		{
+			input:  "https://example.com",
+			isSafe: true,
+		},
+		{
+			input:  "https://google.com",
+			isSafe: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			results, err := sb.CheckURLs(context.TODO(), []string{test.input})
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.Equal(t, test.isSafe, results[0].Safe)
+		})
+	}
+}
+
+// stubAPI serves the full hashes it was created with and records the searches. The lists are served by
+// stubDatabase, so list requests return nothing.
+type stubAPI struct {
+	fullHashes    []*proto.FullHash
+	cacheDuration *durationpb.Duration
+	// blockSearches makes searches wait until the context is done, like a server which doesn't answer in time.
+	blockSearches bool
+	// searchErr fails every search, like a server which is unavailable.
+	searchErr error
+	// afterSearch is called when a search has succeeded.
+	afterSearch func()
+
+	// lock guards the recorded requests, because hash prefixes are searched concurrently.
+	lock     sync.Mutex
+	searched [][]byte
+	searches int
+}
+
+func (sapi *stubAPI) V5alpha1HashLists(ctx context.Context) (*proto.ListHashListsResponse, []byte, error) {
+	return &proto.ListHashListsResponse{}, nil, nil
+}
+
+func (sapi *stubAPI) V5alpha1HashListsBatchGet(ctx context.Context, names []string, versions [][]byte) (*proto.ListHashListsResponse, []byte, error) {
+	return &proto.ListHashListsResponse{}, nil, nil
+}
+
+func (sapi *stubAPI) V5alpha1HashesSearch(ctx context.Context, hashPrefixes [][]byte) (*proto.SearchHashesResponse, []byte, error) {
+	if sapi.blockSearches {
+		<-ctx.Done()
+		return nil, nil, fmt.Errorf("search: %w", ctx.Err())
+	}
+
+	sapi.lock.Lock()
+	defer sapi.lock.Unlock()
+
+	sapi.searched = append(sapi.searched, hashPrefixes...)
+	sapi.searches++
+
+	if sapi.searchErr != nil {
+		return nil, nil, sapi.searchErr
+	}
+
+	if sapi.afterSearch != nil {
+		defer sapi.afterSearch()
+	}
+
+	result := proto.SearchHashesResponse{CacheDuration: sapi.cacheDuration}
+
+	for _, fullHash := range sapi.fullHashes {
+		for _, prefix := range hashPrefixes {
+			if bytes.HasPrefix(fullHash.FullHash, prefix) {
+				result.FullHashes = append(result.FullHashes, fullHash)
+				break
+			}
+		}
+	}
+
+	return &result, nil, nil
+}
+
+func newStubFullHash(expression string, threatType proto.ThreatType) *proto.FullHash {
+	hash := hashFull(expression)
+
+	return &proto.FullHash{
+		FullHash: hash[:],
+		FullHashDetails: []*proto.FullHashDetail{
+			{ThreatType: threatType},
+		},
+	}
+}
+
+// stubDatabase looks hashes up in the lists it was created with.
+type stubDatabase struct {
+	lists []stubList
+}
+
+type stubList struct {
+	name            string
+	version         []byte
+	prefixes        [][]byte
+	threatTypes     []proto.ThreatType
+	likelySafeTypes []proto.LikelySafeType
+}
+
+func (sd *stubDatabase) RunSelfUpdates(ctx context.Context) {}
+
+func (sd *stubDatabase) LookupHashes(hashes [][sha256.Size]byte) []database.HashLookup {
+	lookups := make([]database.HashLookup, len(hashes))
+
+	for i, hash := range hashes {
+		for _, list := range sd.lists {
+			found := slices.ContainsFunc(list.prefixes, func(prefix []byte) bool {
+				return bytes.HasPrefix(hash[:], prefix)
+			})
+			if !found {
+				continue
+			}
+
+			lookups[i].LikelySafeTypes = append(lookups[i].LikelySafeTypes, list.likelySafeTypes...)
+
+			if len(list.threatTypes) > 0 {
+				lookups[i].ThreatLists = append(lookups[i].ThreatLists, database.ListVersion{Name: list.name, Version: list.version})
+			}
+		}
+	}
+
+	return lookups
+}
+
+func (sd *stubDatabase) Close() error {
+	return nil
+}
+
+// hashPrefixesOf returns the prefixes of the given length of the expressions full hashes.
+func hashPrefixesOf(length int, expressions ...string) [][]byte {
+	prefixes := make([][]byte, len(expressions))
+
+	for i, expression := range expressions {
+		hash := hashFull(expression)
+		prefixes[i] = hash[:length]
+	}
+
+	return prefixes
+}
+
+func newStubSafeBrowser(api API, lists ...stubList) *SafeBrowser {
+	return &SafeBrowser{
+		api:           api,
+		localDatabase: &stubDatabase{lists: lists},
+		concurrency:   4,
+		fullHashCache: newFullHashCache(defaultFullHashCacheSize),
+		backoff:       backoff.New(),
+	}
+}
+
+func TestSafeBrowser_CheckURLs_confirmsFullHashes(t *testing.T) {
+	api := &stubAPI{
+		fullHashes: []*proto.FullHash{
+			newStubFullHash("evil.example.com/", proto.ThreatType_SOCIAL_ENGINEERING),
+		},
+	}
+
+	sb := newStubSafeBrowser(api, stubList{
+		name:        "se",
+		prefixes:    hashPrefixesOf(4, "evil.example.com/", "example.com/collision"),
+		threatTypes: []proto.ThreatType{proto.ThreatType_SOCIAL_ENGINEERING},
+	})
+
+	t.Run("confirmed by full hash", func(t *testing.T) {
+		results, err := sb.CheckURLs(context.TODO(), []string{"https://evil.example.com/"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.False(t, results[0].Safe)
+		assert.Equal(t, []proto.ThreatType{proto.ThreatType_SOCIAL_ENGINEERING}, results[0].Threats)
+	})
+
+	t.Run("prefix only match", func(t *testing.T) {
+		api.searched = nil
+
+		results, err := sb.CheckURLs(context.TODO(), []string{"https://example.com/collision"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.True(t, results[0].Safe)
+		assert.Empty(t, results[0].Threats)
+		assert.Len(t, api.searched, 1)
+	})
+
+	t.Run("no local match", func(t *testing.T) {
+		api.searched = nil
+
+		results, err := sb.CheckURLs(context.TODO(), []string{"https://example.org/"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.True(t, results[0].Safe)
+		assert.Empty(t, api.searched)
+	})
+}
+
+func TestSafeBrowser_CheckURLs_globalCache(t *testing.T) {
+	api := &stubAPI{
+		fullHashes: []*proto.FullHash{
+			newStubFullHash("example.com/", proto.ThreatType_MALWARE),
+			newStubFullHash("example.com/some/page.html", proto.ThreatType_MALWARE),
+			newStubFullHash("evil.example.org/", proto.ThreatType_MALWARE),
+		},
+	}
+
+	sb := newStubSafeBrowser(api,
+		stubList{
+			name:            "gc",
+			prefixes:        hashPrefixesOf(16, "example.com/"),
+			likelySafeTypes: []proto.LikelySafeType{proto.LikelySafeType_GENERAL_BROWSING},
+		},
+		stubList{
+			name:        "mw",
+			prefixes:    hashPrefixesOf(4, "example.com/", "example.com/some/page.html", "evil.example.org/"),
+			threatTypes: []proto.ThreatType{proto.ThreatType_MALWARE},
+		},
+	)
+
+	t.Run("threat on a likely safe host", func(t *testing.T) {
+		results, err := sb.CheckURLs(context.TODO(), []string{"https://www.example.com/some/page.html"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.False(t, results[0].Safe)
+		assert.Equal(t, []proto.ThreatType{proto.ThreatType_MALWARE}, results[0].Threats)
+		assert.Equal(t, []proto.LikelySafeType{proto.LikelySafeType_GENERAL_BROWSING}, results[0].LikelySafeTypes)
+
+		require.Len(t, results[0].Matches, 1, "the expression in the global cache is not checked")
+		assert.Equal(t, "example.com/some/page.html", results[0].Matches[0].Expression)
+		assert.True(t, results[0].Matches[0].FullHashConfirmed)
+	})
+
+	t.Run("likely safe", func(t *testing.T) {
+		api.searched = nil
+
+		results, err := sb.CheckURLs(context.TODO(), []string{"https://www.example.com/other/page.html"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.True(t, results[0].Safe)
+		assert.Empty(t, results[0].Matches)
+		assert.Equal(t, []proto.LikelySafeType{proto.LikelySafeType_GENERAL_BROWSING}, results[0].LikelySafeTypes)
+		assert.Empty(t, api.searched, "global cache hits must not be sent to the server")
+	})
+
+	t.Run("not in global cache", func(t *testing.T) {
+		results, err := sb.CheckURLs(context.TODO(), []string{"https://evil.example.org/"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.False(t, results[0].Safe)
+		assert.Empty(t, results[0].LikelySafeTypes)
+		assert.Equal(t, []proto.ThreatType{proto.ThreatType_MALWARE}, results[0].Threats)
+	})
+}
+
+func TestSafeBrowser_CheckURLs_matchDetails(t *testing.T) {
+	api := &stubAPI{
+		fullHashes: []*proto.FullHash{
+			newStubFullHash("evil.example.com/", proto.ThreatType_SOCIAL_ENGINEERING),
+		},
+		cacheDuration: durationpb.New(5 * time.Minute),
+	}
+
+	sb := newStubSafeBrowser(api,
+		stubList{
+			name:        "se",
+			version:     []byte("se-1"),
+			prefixes:    hashPrefixesOf(4, "evil.example.com/", "example.com/"),
+			threatTypes: []proto.ThreatType{proto.ThreatType_SOCIAL_ENGINEERING},
+		},
+		stubList{
+			name:        "mw",
+			version:     []byte("mw-1"),
+			prefixes:    hashPrefixesOf(4, "evil.example.com/"),
+			threatTypes: []proto.ThreatType{proto.ThreatType_MALWARE},
+		},
+	)
+
+	before := time.Now()
+
+	results, err := sb.CheckURLs(context.TODO(), []string{"HTTPS://Evil.Example.com:443/#fragment"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	result := results[0]
+	assert.Equal(t, "HTTPS://Evil.Example.com:443/#fragment", result.URL)
+	assert.Equal(t, "https://evil.example.com/", result.CanonicalURL)
+	assert.False(t, result.Safe)
+	assert.Equal(t, []proto.ThreatType{proto.ThreatType_SOCIAL_ENGINEERING}, result.Threats)
+	require.Len(t, result.Matches, 2)
+
+	confirmed := result.Matches[0]
+	assert.Equal(t, "evil.example.com/", confirmed.Expression)
+	assert.True(t, confirmed.FullHashConfirmed)
+	assert.Equal(t, []proto.ThreatType{proto.ThreatType_SOCIAL_ENGINEERING}, confirmed.ThreatTypes)
+	assert.Equal(t, []MatchedList{{Name: "se", Version: []byte("se-1")}, {Name: "mw", Version: []byte("mw-1")}}, confirmed.Lists)
+	assert.WithinRange(t, confirmed.CacheExpiry, before.Add(5*time.Minute), time.Now().Add(5*time.Minute))
+
+	prefixOnly := result.Matches[1]
+	assert.Equal(t, "example.com/", prefixOnly.Expression)
+	assert.False(t, prefixOnly.FullHashConfirmed)
+	assert.Empty(t, prefixOnly.ThreatTypes)
+	assert.Equal(t, []MatchedList{{Name: "se", Version: []byte("se-1")}}, prefixOnly.Lists)
+	assert.Equal(t, confirmed.CacheExpiry, prefixOnly.CacheExpiry)
+}
+
+func TestSafeBrowser_CheckURLs_threatAttributes(t *testing.T) {
+	canary := newStubFullHash("canary.example.com/", proto.ThreatType_MALWARE)
+	canary.FullHashDetails[0].Attributes = []proto.ThreatAttribute{proto.ThreatAttribute_CANARY}
+
+	frameOnly := newStubFullHash("frame.example.com/", proto.ThreatType_SOCIAL_ENGINEERING)
+	frameOnly.FullHashDetails[0].Attributes = []proto.ThreatAttribute{proto.ThreatAttribute_FRAME_ONLY}
+	// The canary detail of the same hash is skipped, the other one is not
+	frameOnly.FullHashDetails = append(frameOnly.FullHashDetails, &proto.FullHashDetail{
+		ThreatType: proto.ThreatType_MALWARE,
+		Attributes: []proto.ThreatAttribute{proto.ThreatAttribute_CANARY},
+	})
+
+	api := &stubAPI{fullHashes: []*proto.FullHash{canary, frameOnly}}
+
+	sb := newStubSafeBrowser(api, stubList{
+		name:        "mw",
+		prefixes:    hashPrefixesOf(4, "canary.example.com/", "frame.example.com/"),
+		threatTypes: []proto.ThreatType{proto.ThreatType_MALWARE, proto.ThreatType_SOCIAL_ENGINEERING},
+	})
+
+	results, err := sb.CheckURLs(context.TODO(), []string{"https://canary.example.com/", "https://frame.example.com/"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	t.Run("canary", func(t *testing.T) {
+		assert.True(t, results[0].Safe, "canary threats are not enforced")
+		assert.Empty(t, results[0].Threats)
+		require.Len(t, results[0].Matches, 1)
+		assert.True(t, results[0].Matches[0].FullHashConfirmed)
+		assert.Empty(t, results[0].Matches[0].ThreatTypes)
+		assert.Empty(t, results[0].Matches[0].Attributes)
+	})
+
+	t.Run("frame only", func(t *testing.T) {
+		assert.False(t, results[1].Safe)
+		assert.Equal(t, []proto.ThreatType{proto.ThreatType_SOCIAL_ENGINEERING}, results[1].Threats)
+		require.Len(t, results[1].Matches, 1)
+		assert.Equal(t, []proto.ThreatAttribute{proto.ThreatAttribute_FRAME_ONLY}, results[1].Matches[0].Attributes)
+	})
+}
+
+func TestSafeBrowser_CheckURLs_invalidURLs(t *testing.T) {
+	api := &stubAPI{
+		fullHashes: []*proto.FullHash{
+			newStubFullHash("evil.example.com/", proto.ThreatType_MALWARE),
+		},
+	}
+
+	rawURLs := []string{"https://evil.example.com/", "http:///no-host", "https://example.org/", ""}
+
+	tests := []struct {
+		policy       FailurePolicy
+		expectedSafe bool
+		expectedErr  bool
+	}{
+		{policy: FailurePolicyError, expectedSafe: false, expectedErr: true},
+		{policy: FailurePolicyOpen, expectedSafe: true, expectedErr: false},
+		{policy: FailurePolicyClosed, expectedSafe: false, expectedErr: false},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("policy %d", test.policy), func(t *testing.T) {
+			sb := newStubSafeBrowser(api, stubList{
+				name:        "mw",
+				prefixes:    hashPrefixesOf(4, "evil.example.com/"),
+				threatTypes: []proto.ThreatType{proto.ThreatType_MALWARE},
+			})
+			sb.invalidURLPolicy = test.policy
+
+			results, err := sb.CheckURLs(context.TODO(), rawURLs)
+			if test.expectedErr {
+				var invalidURLErr *InvalidURLError
+				require.ErrorAs(t, err, &invalidURLErr)
+				assert.Equal(t, "http:///no-host", invalidURLErr.URL)
+				require.ErrorIs(t, err, urls.ErrEmptyHost)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Len(t, results, len(rawURLs))
+
+			for i, result := range results {
+				assert.Equal(t, rawURLs[i], result.URL)
+			}
+
+			assert.False(t, results[0].Safe)
+			assert.NoError(t, results[0].Err)
+			assert.True(t, results[2].Safe)
+			assert.NoError(t, results[2].Err)
+
+			for _, i := range []int{1, 3} {
+				assert.Equal(t, test.expectedSafe, results[i].Safe)
+				assert.ErrorIs(t, results[i].Err, urls.ErrEmptyHost)
+			}
+		})
+	}
+}
+
+func TestSafeBrowser_CheckURLs_batch(t *testing.T) {
+	const evilPages = 2*maxHashPrefixesPerSearch + 1
+
+	var evilExpressions []string
+	for i := 0; i < evilPages; i++ {
+		evilExpressions = append(evilExpressions, fmt.Sprintf("evil.example.com/%d.html", i))
+	}
+
+	api := &stubAPI{
+		fullHashes: []*proto.FullHash{
+			newStubFullHash("evil.example.com/0.html", proto.ThreatType_MALWARE),
+		},
+	}
+
+	sb := newStubSafeBrowser(api, stubList{
+		name:        "mw",
+		prefixes:    hashPrefixesOf(4, evilExpressions...),
+		threatTypes: []proto.ThreatType{proto.ThreatType_MALWARE},
+	})
+
+	// Every evil page is checked twice, in different forms of the same URL
+	var rawURLs []string
+	for i := 0; i < evilPages; i++ {
+		rawURLs = append(rawURLs, fmt.Sprintf("http://evil.example.com/%d.html", i), fmt.Sprintf("HTTP://EVIL.example.com/%d.html#top", i))
+	}
+	rawURLs = append(rawURLs, "http://example.org/", "http:///")
+
+	results, err := sb.CheckURLs(context.TODO(), rawURLs)
+	require.ErrorIs(t, err, urls.ErrEmptyHost)
+	require.Len(t, results, len(rawURLs))
+
+	for i, result := range results {
+		assert.Equal(t, rawURLs[i], result.URL)
+	}
+
+	assert.False(t, results[0].Safe)
+	assert.False(t, results[1].Safe)
+	assert.Equal(t, []proto.ThreatType{proto.ThreatType_MALWARE}, results[1].Threats)
+
+	for _, result := range results[2 : 2*evilPages] {
+		assert.True(t, result.Safe, result.URL)
+		require.Len(t, result.Matches, 1, result.URL)
+		assert.False(t, result.Matches[0].FullHashConfirmed)
+	}
+
+	assert.True(t, results[len(rawURLs)-2].Safe)
+	assert.Error(t, results[len(rawURLs)-1].Err)
+
+	// Each prefix is searched once, in as few requests as the limit allows
+	assert.Len(t, api.searched, evilPages)
+	assert.Equal(t, 3, api.searches)
+
+	seen := make(map[string]bool)
+	for _, prefix := range api.searched {
+		assert.False(t, seen[string(prefix)], "prefix %x searched twice", prefix)
+		seen[string(prefix)] = true
+	}
+}
+
+func TestSafeBrowser_CheckURLs_deadline(t *testing.T) {
+	api := &stubAPI{blockSearches: true}
+
+	rawURLs := []string{"https://evil.example.com/", "https://example.org/", "http:///"}
+
+	tests := []struct {
+		policy       FailurePolicy
+		expectedSafe bool
+		expectedErr  bool
+	}{
+		{policy: FailurePolicyError, expectedSafe: false, expectedErr: true},
+		{policy: FailurePolicyOpen, expectedSafe: true, expectedErr: false},
+		{policy: FailurePolicyClosed, expectedSafe: false, expectedErr: false},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("policy %d", test.policy), func(t *testing.T) {
+			sb := newStubSafeBrowser(api, stubList{
+				name:        "mw",
+				prefixes:    hashPrefixesOf(4, "evil.example.com/"),
+				threatTypes: []proto.ThreatType{proto.ThreatType_MALWARE},
+			})
+			sb.invalidURLPolicy = FailurePolicyClosed
+			sb.degradedPolicy = test.policy
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			started := time.Now()
+
+			results, err := sb.CheckURLs(ctx, rawURLs)
+			if test.expectedErr {
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Less(t, time.Since(started), time.Second)
+			require.Len(t, results, len(rawURLs))
+
+			// Only the URL which needs the server gets the degraded verdict
+			assert.Equal(t, test.expectedSafe, results[0].Safe)
+			assert.ErrorIs(t, results[0].Err, context.DeadlineExceeded)
+			require.Len(t, results[0].Matches, 1)
+			assert.False(t, results[0].Matches[0].FullHashConfirmed)
+
+			assert.True(t, results[1].Safe)
+			assert.NoError(t, results[1].Err)
+
+			assert.False(t, results[2].Safe)
+			assert.ErrorIs(t, results[2].Err, urls.ErrEmptyHost)
+		})
+	}
+
+	t.Run("done after the search", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		api := &stubAPI{
+			fullHashes:  []*proto.FullHash{newStubFullHash("evil.example.com/", proto.ThreatType_MALWARE)},
+			afterSearch: cancel,
+		}
+
+		sb := newStubSafeBrowser(api, stubList{
+			name:        "mw",
+			prefixes:    hashPrefixesOf(4, "evil.example.com/"),
+			threatTypes: []proto.ThreatType{proto.ThreatType_MALWARE},
+		})
+		sb.degradedPolicy = FailurePolicyError
+
+		results, err := sb.CheckURLs(ctx, []string{"https://evil.example.com/"})
+		require.NoError(t, err, "the search results are used")
+		require.Len(t, results, 1)
+		assert.False(t, results[0].Safe)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, []proto.ThreatType{proto.ThreatType_MALWARE}, results[0].Threats)
+	})
+
+	t.Run("cancelled before the check", func(t *testing.T) {
+		sb := newStubSafeBrowser(api)
+		sb.degradedPolicy = FailurePolicyOpen
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results, err := sb.CheckURLs(ctx, rawURLs)
+		require.NoError(t, err)
+		require.Len(t, results, len(rawURLs))
+
+		for i, result := range results {
+			assert.Equal(t, rawURLs[i], result.URL)
+			assert.True(t, result.Safe)
+			assert.ErrorIs(t, result.Err, context.Canceled)
+		}
+	})
+}
+
+func TestSafeBrowser_CheckURLs_searchFailure(t *testing.T) {
+	searchErr := &APIError{StatusCode: http.StatusServiceUnavailable}
+	api := &stubAPI{searchErr: searchErr}
+
+	rawURLs := []string{"https://evil.example.com/", "https://example.org/", "http:///", "https://www.example.net/"}
+
+	tests := []struct {
+		policy       FailurePolicy
+		expectedSafe bool
+	}{
+		{policy: FailurePolicyError, expectedSafe: false},
+		{policy: FailurePolicyOpen, expectedSafe: true},
+		{policy: FailurePolicyClosed, expectedSafe: false},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("policy %d", test.policy), func(t *testing.T) {
+			sb := newStubSafeBrowser(api,
+				stubList{
+					name:            "gc",
+					prefixes:        hashPrefixesOf(16, "example.net/"),
+					likelySafeTypes: []proto.LikelySafeType{proto.LikelySafeType_GENERAL_BROWSING},
+				},
+				stubList{
+					name:        "mw",
+					prefixes:    hashPrefixesOf(4, "evil.example.com/"),
+					threatTypes: []proto.ThreatType{proto.ThreatType_MALWARE},
+				},
+			)
+			sb.invalidURLPolicy = FailurePolicyClosed
+			sb.degradedPolicy = test.policy
+
+			results, err := sb.CheckURLs(context.TODO(), rawURLs)
+			require.ErrorIs(t, err, ErrUnavailable, "the server error is returned with any policy")
+			require.Len(t, results, len(rawURLs))
+
+			// Only the URL which needs the server gets the degraded verdict
+			assert.Equal(t, test.expectedSafe, results[0].Safe)
+			assert.ErrorIs(t, results[0].Err, searchErr)
+
+			assert.True(t, results[1].Safe)
+			assert.NoError(t, results[1].Err)
+
+			assert.False(t, results[2].Safe)
+			assert.ErrorIs(t, results[2].Err, urls.ErrEmptyHost)
+
+			assert.True(t, results[3].Safe)
+			assert.NoError(t, results[3].Err)
+			assert.Equal(t, []proto.LikelySafeType{proto.LikelySafeType_GENERAL_BROWSING}, results[3].LikelySafeTypes)
+		})
+	}
+}
+
+func TestSafeBrowser_CheckURLs_backoff(t *testing.T) {
+	newSafeBrowser := func(api API) *SafeBrowser {
+		sb := newStubSafeBrowser(api, stubList{
+			name:        "mw",
+			prefixes:    hashPrefixesOf(4, "evil.example.com/"),
+			threatTypes: []proto.ThreatType{proto.ThreatType_MALWARE},
+		})
+		sb.degradedPolicy = FailurePolicyClosed
+		return sb
+	}
+
+	rawURLs := []string{"https://evil.example.com/", "https://example.org/"}
+
+	t.Run("retryable failure", func(t *testing.T) {
+		api := &stubAPI{searchErr: &APIError{StatusCode: http.StatusTooManyRequests}}
+		sb := newSafeBrowser(api)
+
+		_, err := sb.CheckURLs(context.TODO(), rawURLs)
+		require.ErrorIs(t, err, ErrQuotaExceeded)
+		assert.Equal(t, 1, api.searches)
+
+		// No request is sent in the back-off mode, the URLs which need the server get the degraded verdict
+		results, err := sb.CheckURLs(context.TODO(), rawURLs)
+		require.ErrorIs(t, err, ErrBackingOff)
+		assert.Equal(t, 1, api.searches)
+
+		assert.False(t, results[0].Safe)
+		assert.ErrorIs(t, results[0].Err, ErrBackingOff)
+		assert.True(t, results[1].Safe)
+		assert.NoError(t, results[1].Err)
+
+		// A successful search after the back-off mode leaves it
+		sb.backoff.Reset()
+		api.searchErr = nil
+
+		_, err = sb.CheckURLs(context.TODO(), rawURLs)
+		require.NoError(t, err)
+		assert.Equal(t, 2, api.searches)
+		assert.Zero(t, sb.backoff.Failures())
+	})
+
+	t.Run("non-retryable failure", func(t *testing.T) {
+		api := &stubAPI{searchErr: &APIError{StatusCode: http.StatusBadRequest}}
+		sb := newSafeBrowser(api)
+
+		for range 2 {
+			_, err := sb.CheckURLs(context.TODO(), rawURLs)
+			require.ErrorAs(t, err, new(*APIError))
+			require.NotErrorIs(t, err, ErrBackingOff)
+		}
+
+		assert.Equal(t, 2, api.searches)
+	})
+
+	t.Run("done context", func(t *testing.T) {
+		api := &stubAPI{blockSearches: true}
+		sb := newSafeBrowser(api)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, _ = sb.CheckURLs(ctx, rawURLs)
+		assert.Zero(t, sb.backoff.Failures(), "a missed deadline of the caller is not a failure of the server")
+	})
+}
+
+func TestSafeBrowser_CheckURLs_fullHashCache(t *testing.T) {
+	api := &stubAPI{
+		fullHashes: []*proto.FullHash{
+			newStubFullHash("evil.example.com/", proto.ThreatType_MALWARE),
+		},
+		cacheDuration: durationpb.New(time.Minute),
+	}
+
+	sb := newStubSafeBrowser(api, stubList{
+		name:        "mw",
+		prefixes:    hashPrefixesOf(4, "evil.example.com/", "example.com/collision"),
+		threatTypes: []proto.ThreatType{proto.ThreatType_MALWARE},
+	})
+
+	rawURLs := []string{"https://evil.example.com/", "https://example.com/collision"}
+
+	first, err := sb.CheckURLs(context.TODO(), rawURLs)
+	require.NoError(t, err)
+	assert.Equal(t, 1, api.searches)
+	assert.Equal(t, CacheStats{Misses: 2, Entries: 2}, sb.CacheStats())
+
+	second, err := sb.CheckURLs(context.TODO(), rawURLs)
+	require.NoError(t, err)
+	assert.Equal(t, 1, api.searches, "cached prefixes must not be searched again")
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 2, Entries: 2}, sb.CacheStats())
+
+	// Both the confirmed threat and the clean prefix are served from the cache
+	assert.Equal(t, first, second)
+	assert.False(t, second[0].Safe)
+	assert.True(t, second[1].Safe)
+}
+
+// roundTripCounter counts the requests sent through the default transport.
+type roundTripCounter struct {
+	requests atomic.Int32
+}
+
+func (c *roundTripCounter) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// writeHashLists answers a hashLists:batchGet request with empty versions of the requested lists.
+func writeHashLists(t *testing.T, w http.ResponseWriter, r *http.Request) {
+	var response proto.ListHashListsResponse
+	for _, name := range r.URL.Query()["names"] {
+		response.HashLists = append(response.HashLists, &proto.HashList{Name: name, Version: []byte("v1")})
+	}
+
+	body, err := proto2.Marshal(&response)
+	assert.NoError(t, err)
+
+	_, _ = w.Write(body)
+}
+
+func TestNewSafeBrowser_httpOptions(t *testing.T) {
+	var paths []string
+	var lock sync.Mutex
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		paths = append(paths, r.URL.Path)
+		lock.Unlock()
+
+		writeHashLists(t, w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	httpClient := &http.Client{}
+	transport := &roundTripCounter{}
+
+	sb, err := NewSafeBrowser(
+		WithAPIKey("test-key"),
+		WithBaseURL(srv.URL),
+		WithHTTPClient(httpClient),
+		WithTransport(transport),
+		WithRequestTimeout(time.Second),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sb.Close() })
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	require.Equal(t, []string{"/v5alpha1/hashLists:batchGet"}, paths)
+	require.EqualValues(t, 1, transport.requests.Load())
+	require.Nil(t, httpClient.Transport, "the given client is not modified")
+}
+
+// deadlineAPI records the deadline of the list requests.
+type deadlineAPI struct {
+	stubAPI
+	deadline    time.Time
+	hasDeadline bool
+}
+
+func (dapi *deadlineAPI) V5alpha1HashListsBatchGet(ctx context.Context, names []string, versions [][]byte) (*proto.ListHashListsResponse, []byte, error) {
+	dapi.deadline, dapi.hasDeadline = ctx.Deadline()
+
+	var result proto.ListHashListsResponse
+	for _, name := range names {
+		result.HashLists = append(result.HashLists, &proto.HashList{Name: name})
+	}
+
+	return &result, nil, nil
+}
+
+func TestNewSafeBrowser_startupTimeout(t *testing.T) {
+	t.Run("request timeout", func(t *testing.T) {
+		api := &deadlineAPI{}
+
+		sb, err := NewSafeBrowser(WithAPIClient(api), WithRequestTimeout(10*time.Minute))
+		require.NoError(t, err)
+		require.NoError(t, sb.Close())
+
+		require.True(t, api.hasDeadline)
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), api.deadline, time.Minute,
+			"the first download of the lists is not cut shorter than the request timeout")
+	})
+
+	t.Run("no request timeout", func(t *testing.T) {
+		api := &deadlineAPI{}
+
+		sb, err := NewSafeBrowser(WithAPIClient(api), WithRequestTimeout(0))
+		require.NoError(t, err)
+		require.NoError(t, sb.Close())
+
+		assert.False(t, api.hasDeadline)
+	})
+}
+
+func TestNewSafeBrowser_apiError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"error": {"code": 400, "message": "API key not valid. Please pass a valid API key.", `+
+			`"status": "INVALID_ARGUMENT", "details": [{"@type": "type.googleapis.com/google.rpc.ErrorInfo", `+
+			`"reason": "API_KEY_INVALID", "domain": "googleapis.com"}]}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := NewSafeBrowser(WithAPIKey("revoked-key"), WithBaseURL(srv.URL))
+	require.ErrorIs(t, err, ErrInvalidAPIKey)
+	require.NotErrorIs(t, err, ErrUnavailable)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "INVALID_ARGUMENT", apiErr.Status)
+	assert.Equal(t, "API_KEY_INVALID", apiErr.Reason())
+	assert.NotContains(t, err.Error(), "revoked-key")
+}
+
+func TestNewSafeBrowser_logsNoSecrets(t *testing.T) {
+	const key = "secret-api-key"
+
+	var available atomic.Bool
+	available.Store(true)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, key, r.Header.Get("X-Goog-Api-Key"))
+		assert.False(t, r.URL.Query().Has("key"), "the key is not sent in the query")
+
+		if !available.Load() {
+			// The error echoes the key, it must not end up in the logged error.
+			http.Error(w, "backend error for key "+key, http.StatusServiceUnavailable)
+			return
+		}
+
+		writeHashLists(t, w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	var logs bytes.Buffer
+	logger := log.New(&logs, "", 0)
+	databasePath := path.Join(t.TempDir(), "gsb.db")
+
+	sb, err := NewSafeBrowser(WithAPIKey(key), WithBaseURL(srv.URL), WithLogger(logger), WithDatabasePath(databasePath))
+	require.NoError(t, err)
+	require.NoError(t, sb.Close())
+
+	available.Store(false)
+
+	sb, err = NewSafeBrowser(
+		WithAPIKey(key),
+		WithBaseURL(srv.URL),
+		WithLogger(logger),
+		WithDatabasePath(databasePath),
+		WithDatabaseMaxAge(0),
+	)
+	require.NoError(t, err, "the stale database is used while the API is unavailable")
+	require.NoError(t, sb.Close())
+
+	require.Contains(t, logs.String(), "send request: "+srv.URL)
+	require.Contains(t, logs.String(), "status=503")
+	require.NotContains(t, logs.String(), key)
+}